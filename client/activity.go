@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var activityPrintFormats = []string{
+	"table",
+	"json",
+	"markdown",
+}
+
+// activityCount is the amount of events with a value, e.g. of a service.
+type activityCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// activityFailure is a failed action of the initiator.
+type activityFailure struct {
+	EventID string `json:"event_id"`
+	Time    string `json:"time"`
+	Service string `json:"service"`
+	Action  string `json:"action"`
+	Target  string `json:"target"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// activityReport summarizes the events of an initiator.
+type activityReport struct {
+	Initiators      []string          `json:"initiators"`
+	FirstSeen       string            `json:"first_seen"`
+	LastSeen        string            `json:"last_seen"`
+	Events          int               `json:"events"`
+	Services        []activityCount   `json:"services"`
+	Actions         []activityCount   `json:"actions"`
+	Outcomes        []activityCount   `json:"outcomes"`
+	SourceAddresses []activityCount   `json:"source_addresses"`
+	UserAgents      []activityCount   `json:"user_agents"`
+	FailedActions   []activityFailure `json:"failed_actions"`
+}
+
+// activityCounter counts the events per value.
+type activityCounter map[string]int
+
+func (c activityCounter) Add(name string) {
+	if name != "" {
+		c[name]++
+	}
+}
+
+// Sorted returns the counts ordered by the amount of events.
+func (c activityCounter) Sorted() []activityCount {
+	result := make([]activityCount, 0, len(c))
+	for name, count := range c {
+		result = append(result, activityCount{Name: name, Count: count})
+	}
+	slices.SortFunc(result, func(a, b activityCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return result
+}
+
+// buildActivityReport summarizes the chronologically ordered events.
+func buildActivityReport(allEvents []events.Event) activityReport {
+	report := activityReport{
+		Events:        len(allEvents),
+		FailedActions: []activityFailure{},
+	}
+	initiators := make(activityCounter)
+	services := make(activityCounter)
+	actions := make(activityCounter)
+	outcomes := make(activityCounter)
+	addresses := make(activityCounter)
+	agents := make(activityCounter)
+
+	for _, evt := range allEvents {
+		initiator := evt.Initiator.ID
+		if evt.Initiator.Name != "" {
+			initiator = fmt.Sprintf("%s (%s)", evt.Initiator.Name, evt.Initiator.ID)
+		}
+		initiators.Add(initiator)
+		services.Add(evt.Observer.TypeURI)
+		actions.Add(string(evt.Action))
+		outcomes.Add(string(evt.Outcome))
+		if evt.Initiator.Host != nil {
+			addresses.Add(evt.Initiator.Host.Address)
+			agents.Add(evt.Initiator.Host.Agent)
+		}
+
+		if evt.Outcome == cadf.FailureOutcome {
+			report.FailedActions = append(report.FailedActions, activityFailure{
+				EventID: evt.ID,
+				Time:    evt.EventTime,
+				Service: evt.Observer.TypeURI,
+				Action:  string(evt.Action),
+				Target:  strings.TrimSpace(evt.Target.TypeURI + " " + evt.Target.ID),
+				Reason:  strings.TrimSpace(evt.Reason.ReasonCode + " " + evt.Reason.ReasonType),
+			})
+		}
+	}
+
+	if len(allEvents) > 0 {
+		report.FirstSeen = allEvents[0].EventTime
+		report.LastSeen = allEvents[len(allEvents)-1].EventTime
+	}
+	for _, v := range initiators.Sorted() {
+		report.Initiators = append(report.Initiators, v.Name)
+	}
+	report.Services = services.Sorted()
+	report.Actions = actions.Sorted()
+	report.Outcomes = outcomes.Sorted()
+	report.SourceAddresses = addresses.Sorted()
+	report.UserAgents = agents.Sorted()
+
+	return report
+}
+
+// renderActivityTable renders a section of the activity report as a table
+// or as a Markdown table with a heading.
+func renderActivityTable(w io.Writer, title string, header []string, records [][]string, markdown bool) error {
+	opts := []tablewriter.Option{tablewriter.WithRowAlignment(tw.AlignRight)}
+	if markdown {
+		fmt.Fprintf(w, "\n## %s\n\n", title)
+		opts = []tablewriter.Option{
+			tablewriter.WithRenderer(renderer.NewMarkdown()),
+			tablewriter.WithHeaderAutoFormat(tw.Off),
+			tablewriter.WithHeaderAlignment(tw.AlignLeft),
+			tablewriter.WithRowAlignment(tw.AlignLeft),
+		}
+	} else {
+		fmt.Fprintf(w, "\n%s:\n", title)
+	}
+	if len(records) == 0 {
+		fmt.Fprintln(w, "none")
+		return nil
+	}
+
+	table := tablewriter.NewTable(w, opts...)
+	table.Header(header)
+	if err := table.Bulk(records); err != nil {
+		return fmt.Errorf("error appending rows to table: %w", err)
+	}
+	if err := table.Render(); err != nil {
+		return fmt.Errorf("error rendering table: %w", err)
+	}
+	return nil
+}
+
+func printActivityReport(w io.Writer, report activityReport, format string) error {
+	if format == "json" {
+		jsonReport, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", jsonReport)
+		return nil
+	}
+	if !slices.Contains(activityPrintFormats, format) {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	markdown := format == "markdown"
+	summary := [][]string{
+		{"Initiators", strings.Join(report.Initiators, ", ")},
+		{"First Seen", formatEventTime(report.FirstSeen)},
+		{"Last Seen", formatEventTime(report.LastSeen)},
+		{"Events", strconv.Itoa(report.Events)},
+	}
+	if markdown {
+		fmt.Fprintln(w, "# Activity Report")
+	}
+	if err := renderActivityTable(w, "Summary", []string{"Key", "Value"}, summary, markdown); err != nil {
+		return err
+	}
+
+	sections := []struct {
+		Title  string
+		Name   string
+		Counts []activityCount
+	}{
+		{"Services", "Service", report.Services},
+		{"Actions", "Action", report.Actions},
+		{"Outcomes", "Outcome", report.Outcomes},
+		{"Source Addresses", "Address", report.SourceAddresses},
+		{"User Agents", "User Agent", report.UserAgents},
+	}
+	for _, s := range sections {
+		records := make([][]string, len(s.Counts))
+		for i, v := range s.Counts {
+			records[i] = []string{v.Name, strconv.Itoa(v.Count)}
+		}
+		if err := renderActivityTable(w, s.Title, []string{s.Name, "Count"}, records, markdown); err != nil {
+			return err
+		}
+	}
+
+	records := make([][]string, len(report.FailedActions))
+	for i, v := range report.FailedActions {
+		records[i] = []string{formatEventTime(v.Time), v.Service, v.Action, v.Target, v.Reason, v.EventID}
+	}
+	return renderActivityTable(w, "Failed Actions", []string{"Time", "Service", "Action", "Target", "Reason", "ID"}, records, markdown)
+}
+
+// ActivityCmd represents the activity command
+var ActivityCmd = &cobra.Command{
+	Use:   "activity",
+	Args:  cobra.ExactArgs(0),
+	Short: "Summarize the activity of a user or an application credential",
+	Long: `Summarize the events of an initiator over a period for access reviews: the events per service, action and outcome,
+the first and the last event, the source addresses and user agents and the failed actions.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		if viper.GetString("initiator-id") == "" && viper.GetString("initiator-name") == "" && viper.GetString("app-credential") == "" {
+			return errors.New("one of --initiator-id, --initiator-name or --app-credential is required")
+		}
+		if err := verifyTimeFlags(); err != nil {
+			return err
+		}
+
+		return verifyGlobalFlags(nil, activityPrintFormats...)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		timeFilter, err := buildTimeFilter(time.Now())
+		if err != nil {
+			return err
+		}
+		projectID, domainID := getScope()
+		// Hermes cannot filter by the application credential, therefore
+		// events mentioning it are searched and filtered locally
+		appCredential := viper.GetString("app-credential")
+		listOpts := events.ListOpts{
+			Limit:         maxOffset,
+			InitiatorID:   viper.GetString("initiator-id"),
+			InitiatorName: viper.GetString("initiator-name"),
+			Search:        appCredential,
+			ProjectID:     projectID,
+			DomainID:      domainID,
+			Time:          timeFilter,
+			Sort:          "time:asc",
+		}
+
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+
+		var allEvents []events.Event
+		var bar *pb.ProgressBar
+		err = getEvents(ctx, client, listOpts, 0, viper.GetInt("concurrency"), &bar, func(page []events.Event) error {
+			for _, evt := range page {
+				if appCredential == "" || evt.Initiator.AppCredentialID == appCredential {
+					allEvents = append(allEvents, evt)
+				}
+			}
+			return nil
+		})
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list the events of the initiator: %w", err)
+		}
+
+		return printActivityReport(os.Stdout, buildActivityReport(allEvents), viper.GetString("format"))
+	},
+}
+
+func init() {
+	initActivityCmdFlags()
+	RootCmd.AddCommand(ActivityCmd)
+}
+
+func initActivityCmdFlags() {
+	ActivityCmd.Flags().StringP("initiator-id", "", "", "summarize the events of an initiator ID")
+	ActivityCmd.Flags().StringP("initiator-name", "", "", "summarize the events of an initiator name")
+	ActivityCmd.Flags().StringP("app-credential", "", "", "summarize the events of an application credential ID")
+	addScopeFlags(ActivityCmd.Flags(), "summarize events of")
+	addTimeFilterFlags(ActivityCmd.Flags())
+	ActivityCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestBuildActivityReport(t *testing.T) {
+	initiator := cadf.Resource{ID: "u-1", Name: "alice", Host: &cadf.Host{Address: "10.0.0.1", Agent: "openstacksdk"}}
+	otherHost := initiator
+	otherHost.Host = &cadf.Host{Address: "10.0.0.2", Agent: "openstacksdk"}
+	allEvents := []events.Event{
+		{ID: "1", EventTime: "2024-01-15T09:00:00.000+0000", Action: "create", Outcome: "success", Initiator: initiator, Observer: cadf.Resource{TypeURI: "service/compute"}, Target: cadf.Resource{TypeURI: "compute/server", ID: "vm-1"}},
+		{ID: "2", EventTime: "2024-01-15T10:00:00.000+0000", Action: "delete", Outcome: "failure", Reason: cadf.Reason{ReasonCode: "403"}, Initiator: otherHost, Observer: cadf.Resource{TypeURI: "service/network"}, Target: cadf.Resource{TypeURI: "network/port", ID: "port-1"}},
+		{ID: "3", EventTime: "2024-01-15T11:00:00.000+0000", Action: "create", Outcome: "success", Initiator: initiator, Observer: cadf.Resource{TypeURI: "service/compute"}, Target: cadf.Resource{TypeURI: "compute/server", ID: "vm-2"}},
+	}
+
+	report := buildActivityReport(allEvents)
+	expected := activityReport{
+		Initiators:      []string{"alice (u-1)"},
+		FirstSeen:       "2024-01-15T09:00:00.000+0000",
+		LastSeen:        "2024-01-15T11:00:00.000+0000",
+		Events:          3,
+		Services:        []activityCount{{"service/compute", 2}, {"service/network", 1}},
+		Actions:         []activityCount{{"create", 2}, {"delete", 1}},
+		Outcomes:        []activityCount{{"success", 2}, {"failure", 1}},
+		SourceAddresses: []activityCount{{"10.0.0.1", 2}, {"10.0.0.2", 1}},
+		UserAgents:      []activityCount{{"openstacksdk", 3}},
+		FailedActions: []activityFailure{
+			{EventID: "2", Time: "2024-01-15T10:00:00.000+0000", Service: "service/network", Action: "delete", Target: "network/port port-1", Reason: "403"},
+		},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("expected %+v, got %+v", expected, report)
+	}
+
+	var buf bytes.Buffer
+	if err := printActivityReport(&buf, report, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# Activity Report", "## Failed Actions", "| service/network |"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected the Markdown report to contain %q:\n%s", s, buf.String())
+		}
+	}
+}
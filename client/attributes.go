@@ -61,15 +61,12 @@ var AttributesCmd = &cobra.Command{
 
 		format := viper.GetString("format")
 
-		projectID := viper.GetString("project-id")
-		if viper.GetBool("all-projects") {
-			projectID = "*"
-		}
-
+		projectID, domainID := getScope()
 		listOpts := attributes.ListOpts{
 			Limit:     viper.GetInt("limit"),
 			MaxDepth:  viper.GetInt("max-depth"),
 			ProjectID: projectID,
+			DomainID:  domainID,
 		}
 
 		var allAttributes []string
@@ -124,6 +121,5 @@ func init() {
 func initAttributesCmdFlags() {
 	AttributesCmd.Flags().UintP("limit", "l", 0, "limit an amount of attributes in output")
 	AttributesCmd.Flags().UintP("max-depth", "", 0, "limit the level of detail of hierarchical values")
-	AttributesCmd.Flags().StringP("project-id", "", "", "filter attributes by the project or domain ID (admin only)")
-	AttributesCmd.Flags().BoolP("all-projects", "A", false, "include all projects and domains (admin only) (alias for --project-id '*')")
+	addScopeFlags(AttributesCmd.Flags(), "filter attributes by")
 }
@@ -0,0 +1,625 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// cacheSettleTime is the time, after which Hermes is expected to contain all
+// events of a time window. Newer events are cached, but their time window is
+// not recorded as complete.
+const cacheSettleTime = 10 * time.Minute
+
+// cacheScopeEnv contains the environment variables, which define the scope of
+// the Hermes queries, e.g. a project scoped token returns events of the
+// project only.
+var cacheScopeEnv = []string{
+	"OS_CLOUD",
+	"OS_AUTH_URL",
+	"OS_REGION_NAME",
+	"OS_PROJECT_ID",
+	"OS_PROJECT_NAME",
+	"OS_PROJECT_DOMAIN_ID",
+	"OS_PROJECT_DOMAIN_NAME",
+	"OS_DOMAIN_ID",
+	"OS_DOMAIN_NAME",
+}
+
+// eventCache is an on-disk cache of Hermes events. Events are stored by their
+// ID, queries store the time windows, which were completely fetched for their
+// filters, together with the IDs of the matching events.
+type eventCache struct {
+	Dir string
+}
+
+// cachedWindow is a completely fetched half-open time window of a cached
+// query
+type cachedWindow struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	FetchedAt time.Time `json:"fetched_at"`
+	IDs       []string  `json:"ids"`
+}
+
+// cachedQuery contains the cached time windows of a filter combination
+type cachedQuery struct {
+	Key     string            `json:"-"`
+	Filters events.ListOpts   `json:"filters"`
+	Scope   map[string]string `json:"scope"`
+	Windows []cachedWindow    `json:"windows"`
+}
+
+func openEventCache() (*eventCache, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect the cache directory: %w", err)
+	}
+	return &eventCache{Dir: filepath.Join(dir, "hermescli")}, nil
+}
+
+// tempFilePrefix is the prefix of the files, which are written by
+// writeFileAtomic and renamed after they are complete
+const tempFilePrefix = ".tmp-"
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (c *eventCache) eventPath(id string) (string, error) {
+	if len(id) < 2 || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid event ID: %q", id)
+	}
+	return filepath.Join(c.Dir, "events", id[:2], id+".json"), nil
+}
+
+// GetEvent returns false, when the event is not cached.
+func (c *eventCache) GetEvent(id string) (*events.Event, bool, error) {
+	path, err := c.eventPath(id)
+	if err != nil {
+		return nil, false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read the cached %s event: %w", id, err)
+	}
+	var evt events.Event
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil, false, fmt.Errorf("failed to parse the cached %s event: %w", id, err)
+	}
+	return &evt, true, nil
+}
+
+func (c *eventCache) PutEvents(allEvents []events.Event) error {
+	for _, evt := range allEvents {
+		path, err := c.eventPath(evt.ID)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(evt)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return fmt.Errorf("failed to cache the %s event: %w", evt.ID, err)
+		}
+	}
+	return nil
+}
+
+// newCachedQuery returns an empty query for the filters of the list options.
+// Time filters, the sort order and the paging are not part of the query.
+func newCachedQuery(listOpts events.ListOpts) (*cachedQuery, error) {
+	listOpts.Time = nil
+	listOpts.Sort = ""
+	listOpts.Limit = 0
+	listOpts.Offset = 0
+
+	q := &cachedQuery{
+		Filters: listOpts,
+		Scope:   make(map[string]string),
+	}
+	for _, name := range cacheScopeEnv {
+		if v := os.Getenv(name); v != "" {
+			q.Scope[name] = v
+		}
+	}
+
+	data, err := json.Marshal([]any{q.Filters, q.Scope})
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	q.Key = hex.EncodeToString(sum[:])
+
+	return q, nil
+}
+
+func (c *eventCache) queryPath(key string) string {
+	return filepath.Join(c.Dir, "queries", key+".json")
+}
+
+// LoadQuery returns the cached time windows for the filters of the list
+// options.
+func (c *eventCache) LoadQuery(listOpts events.ListOpts) (*cachedQuery, error) {
+	q, err := newCachedQuery(listOpts)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(c.queryPath(q.Key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cached query: %w", err)
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("failed to parse the cached query: %w", err)
+	}
+	return q, nil
+}
+
+func (c *eventCache) SaveQuery(q *cachedQuery) error {
+	if len(q.Windows) == 0 {
+		err := os.Remove(c.queryPath(q.Key))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.queryPath(q.Key), data)
+}
+
+// Missing returns the parts of the time window, which are not cached.
+func (q *cachedQuery) Missing(w timeWindow) []timeWindow {
+	windows := slices.Clone(q.Windows)
+	slices.SortFunc(windows, func(a, b cachedWindow) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var result []timeWindow
+	cursor := w.Start
+	for _, cw := range windows {
+		if !cw.End.After(cursor) {
+			continue
+		}
+		if !cw.Start.Before(w.End) {
+			break
+		}
+		if cw.Start.After(cursor) {
+			result = append(result, timeWindow{Start: cursor, End: cw.Start})
+		}
+		cursor = cw.End
+	}
+	if cursor.Before(w.End) {
+		result = append(result, timeWindow{Start: cursor, End: w.End})
+	}
+
+	return result
+}
+
+// Bounds returns the time window covering all cached windows.
+func (q *cachedQuery) Bounds() timeWindow {
+	var w timeWindow
+	for i, cw := range q.Windows {
+		if i == 0 || cw.Start.Before(w.Start) {
+			w.Start = cw.Start
+		}
+		if i == 0 || cw.End.After(w.End) {
+			w.End = cw.End
+		}
+	}
+	return w
+}
+
+func eventInWindow(evt events.Event, w timeWindow) bool {
+	t, err := parseTime(evt.EventTime)
+	if err != nil {
+		return false
+	}
+	return w.Contains(t)
+}
+
+// fetchMissingWindows fetches the time windows, which are not cached yet, and
+// records them in the query. It returns the fetched events.
+func (c *eventCache) fetchMissingWindows(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, q *cachedQuery, w timeWindow, concurrency int) (map[string]events.Event, error) {
+	now := time.Now()
+	settled := now.Add(-cacheSettleTime).Truncate(time.Second)
+	fetched := make(map[string]events.Event)
+
+	for _, missing := range q.Missing(w) {
+		complete := timeWindow{Start: missing.Start, End: minTime(missing.End, settled)}
+		var ids []string
+
+		opts := missing.applyTo(listOpts)
+		opts.Sort = "time:asc"
+		opts.Limit = maxOffset
+
+		var bar *pb.ProgressBar
+		err := getEvents(ctx, client, opts, 0, concurrency, &bar, func(page []events.Event) error {
+			if err := c.PutEvents(page); err != nil {
+				return err
+			}
+			for _, evt := range page {
+				fetched[evt.ID] = evt
+				if eventInWindow(evt, complete) {
+					ids = append(ids, evt.ID)
+				}
+			}
+			return nil
+		})
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if complete.Start.Before(complete.End) {
+			q.Windows = append(q.Windows, cachedWindow{
+				Start:     complete.Start,
+				End:       complete.End,
+				FetchedAt: now,
+				IDs:       ids,
+			})
+		}
+	}
+
+	return fetched, c.SaveQuery(q)
+}
+
+// listCachedEvents returns the events matching the list options from the
+// cache. Time windows, which are not cached yet, are fetched from Hermes
+// first. When client is nil, only cached events are returned.
+func (c *eventCache) listCachedEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, concurrency int) ([]events.Event, error) {
+	w, hasStart, hasEnd, ok := parseTimeWindow(listOpts.Time)
+	if !ok {
+		return nil, errors.New("cached queries do not support the exact time filter, use the time-start and time-end flags")
+	}
+
+	q, err := c.LoadQuery(listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[string]events.Event)
+	if client == nil {
+		if len(q.Windows) == 0 {
+			log.Printf("[WARNING] No events are cached for the specified filters")
+			return nil, nil
+		}
+		bounds := q.Bounds()
+		if !hasStart {
+			w.Start = bounds.Start
+		}
+		if !hasEnd {
+			w.End = bounds.End
+		}
+		for _, missing := range q.Missing(w) {
+			log.Printf("[WARNING] Events from %s till %s are not cached", missing.Start.Format(time.RFC3339), missing.End.Format(time.RFC3339))
+		}
+	} else {
+		if !hasStart || !hasEnd {
+			total, err := countEvents(ctx, client, listOpts)
+			if err != nil || total == 0 {
+				return nil, err
+			}
+			if w, ok, err = getTimeWindow(ctx, client, listOpts); err != nil || !ok {
+				return nil, err
+			}
+		}
+		fetched, err = c.fetchMissingWindows(ctx, client, listOpts, q, w, concurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []events.Event
+	for _, evt := range fetched {
+		if eventInWindow(evt, w) {
+			result = append(result, evt)
+		}
+	}
+	for _, cw := range q.Windows {
+		if !cw.End.After(w.Start) || !cw.Start.Before(w.End) {
+			continue
+		}
+		for _, id := range cw.IDs {
+			if _, ok := fetched[id]; ok {
+				continue
+			}
+			evt, ok, err := c.GetEvent(id)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, fmt.Errorf("the cached %s event is missing, run \"hermescli cache clear\" to reset the cache", id)
+			}
+			if eventInWindow(*evt, w) {
+				fetched[id] = *evt
+				result = append(result, *evt)
+			}
+		}
+	}
+
+	sortEvents(result, parseSortKeys(listOpts.Sort))
+	return result, nil
+}
+
+// cacheInfo contains the statistics of the cache
+type cacheInfo struct {
+	Path    string `json:"path" yaml:"path"`
+	Size    int64  `json:"size" yaml:"size"`
+	Events  int    `json:"events" yaml:"events"`
+	Queries int    `json:"queries" yaml:"queries"`
+	Windows int    `json:"windows" yaml:"windows"`
+}
+
+func (c *eventCache) Info() (cacheInfo, error) {
+	info := cacheInfo{Path: c.Dir}
+	err := filepath.WalkDir(c.Dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		info.Size += fi.Size()
+		if strings.HasPrefix(d.Name(), tempFilePrefix) {
+			// left behind by an interrupted write
+			return nil
+		}
+
+		switch filepath.Base(filepath.Dir(path)) {
+		case "queries":
+			info.Queries++
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			var q cachedQuery
+			if err := json.Unmarshal(data, &q); err != nil {
+				return fmt.Errorf("failed to parse the %s cached query: %w", path, err)
+			}
+			info.Windows += len(q.Windows)
+		default:
+			info.Events++
+		}
+		return nil
+	})
+	return info, err
+}
+
+// Prune removes events and time windows fetched before the cutoff time.
+func (c *eventCache) Prune(cutoff time.Time) (int, error) {
+	var removed int
+	err := filepath.WalkDir(filepath.Join(c.Dir, "events"), func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		// events are rewritten on every fetch, the modification time is
+		// the fetch time
+		if fi.ModTime().Before(cutoff) {
+			if !strings.HasPrefix(d.Name(), tempFilePrefix) {
+				removed++
+			}
+			return os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return removed, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(c.Dir, "queries"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return removed, nil
+	}
+	if err != nil {
+		return removed, err
+	}
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(c.queryPath(key))
+		if err != nil {
+			return removed, err
+		}
+		q := cachedQuery{Key: key}
+		if err := json.Unmarshal(data, &q); err != nil {
+			return removed, fmt.Errorf("failed to parse the %s cached query: %w", key, err)
+		}
+		q.Windows = slices.DeleteFunc(q.Windows, func(cw cachedWindow) bool {
+			return cw.FetchedAt.Before(cutoff)
+		})
+		if err := c.SaveQuery(&q); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
+
+// CacheCmd represents the cache command
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Args:  cobra.NoArgs,
+	Short: "Manage the local event cache",
+	Long: `Manage the local event cache.
+The list and show commands store fetched events in the cache, when the --cache flag is specified.`,
+}
+
+// CacheInfoCmd represents the cache info command
+var CacheInfoCmd = &cobra.Command{
+	Use:   "info",
+	Args:  cobra.NoArgs,
+	Short: "Show the cache size",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := openEventCache()
+		if err != nil {
+			return err
+		}
+		info, err := cache.Info()
+		if err != nil {
+			return fmt.Errorf("failed to inspect the cache: %w", err)
+		}
+
+		switch viper.GetString("format") {
+		case "json":
+			jsonInfo, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", jsonInfo)
+		case "yaml":
+			yamlInfo, err := yaml.Marshal(info)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s", yamlInfo)
+		default:
+			table := tablewriter.NewTable(os.Stdout,
+				tablewriter.WithRowAlignment(tw.AlignRight),
+			)
+			table.Header("Key", "Value")
+			rows := [][]string{
+				{"Path", info.Path},
+				{"Size", fmt.Sprintf("%.1fMB", float64(info.Size)/1024/1024)},
+				{"Events", strconv.Itoa(info.Events)},
+				{"Queries", strconv.Itoa(info.Queries)},
+				{"Windows", strconv.Itoa(info.Windows)},
+			}
+			if err := table.Bulk(rows); err != nil {
+				return fmt.Errorf("error appending rows to table: %w", err)
+			}
+			if err := table.Render(); err != nil {
+				return fmt.Errorf("error rendering table: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
+
+// CachePruneCmd represents the cache prune command
+var CachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Args:  cobra.NoArgs,
+	Short: "Remove cached events older than the specified age",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cutoff, err := parseTimeExpr(viper.GetString("older-than"), time.Now(), timezone)
+		if err != nil {
+			return fmt.Errorf("failed to parse older-than: %w", err)
+		}
+
+		cache, err := openEventCache()
+		if err != nil {
+			return err
+		}
+		removed, err := cache.Prune(cutoff)
+		if err != nil {
+			return fmt.Errorf("failed to prune the cache: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Removed %d cached events\n", removed)
+		return nil
+	},
+}
+
+// CacheClearCmd represents the cache clear command
+var CacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Args:  cobra.NoArgs,
+	Short: "Remove all cached events",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := openEventCache()
+		if err != nil {
+			return err
+		}
+		if err := os.RemoveAll(cache.Dir); err != nil {
+			return fmt.Errorf("failed to clear the cache: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	CachePruneCmd.Flags().StringP("older-than", "", "30d", "remove events fetched before this time, e.g. 7d or 2024-05-01")
+	CacheCmd.AddCommand(CacheInfoCmd)
+	CacheCmd.AddCommand(CachePruneCmd)
+	CacheCmd.AddCommand(CacheClearCmd)
+	RootCmd.AddCommand(CacheCmd)
+}
@@ -0,0 +1,132 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestCachedQueryMissing(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	q := cachedQuery{
+		Windows: []cachedWindow{
+			{Start: at(30), End: at(40)},
+			{Start: at(10), End: at(20)},
+		},
+	}
+
+	missing := q.Missing(timeWindow{Start: at(0), End: at(60)})
+	expected := []timeWindow{
+		{Start: at(0), End: at(10)},
+		{Start: at(20), End: at(30)},
+		{Start: at(40), End: at(60)},
+	}
+	if len(missing) != len(expected) {
+		t.Fatalf("expected %d missing windows, got %v", len(expected), missing)
+	}
+	for i := range expected {
+		if !missing[i].Start.Equal(expected[i].Start) || !missing[i].End.Equal(expected[i].End) {
+			t.Errorf("expected missing window %v, got %v", expected[i], missing[i])
+		}
+	}
+
+	if missing := q.Missing(timeWindow{Start: at(12), End: at(18)}); len(missing) != 0 {
+		t.Errorf("expected a cached window, got missing %v", missing)
+	}
+}
+
+func TestEventCache(t *testing.T) {
+	cache := &eventCache{Dir: t.TempDir()}
+
+	if err := cache.PutEvents([]events.Event{{ID: "1878df7c-d3ec-52d0-8b56-11ad68d25102", Action: "update"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	evt, ok, err := cache.GetEvent("1878df7c-d3ec-52d0-8b56-11ad68d25102")
+	if err != nil || !ok {
+		t.Fatalf("expected a cached event, got %v, %s", ok, err)
+	}
+	if evt.Action != "update" {
+		t.Errorf("expected the cached event action to be update, got %s", evt.Action)
+	}
+
+	if _, ok, err := cache.GetEvent("unknown"); err != nil || ok {
+		t.Errorf("expected no cached event, got %v, %v", ok, err)
+	}
+	if _, _, err := cache.GetEvent("../etc"); err == nil {
+		t.Error("expected an invalid event ID error")
+	}
+}
+
+func TestEventCacheInfo(t *testing.T) {
+	cache := &eventCache{Dir: t.TempDir()}
+
+	if err := cache.PutEvents([]events.Event{{ID: "1878df7c-d3ec-52d0-8b56-11ad68d25102"}}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := cache.eventPath("1878df7c-d3ec-52d0-8b56-11ad68d25102")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an interrupted write leaves a temporary file behind
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), tempFilePrefix+"123"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := cache.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Events != 1 {
+		t.Errorf("expected 1 cached event, got %d", info.Events)
+	}
+}
+
+func TestCachedEventInScope(t *testing.T) {
+	t.Setenv("OS_PROJECT_ID", "")
+	t.Setenv("OS_DOMAIN_ID", "")
+
+	evt := events.Event{
+		Initiator: cadf.Resource{ProjectID: "p-1", DomainID: "d-1"},
+		Target:    cadf.Resource{ProjectID: "p-2"},
+	}
+	cases := []struct {
+		ProjectID string
+		DomainID  string
+		Expected  bool
+	}{
+		{"", "", true},
+		{"p-1", "", true},
+		{"p-2", "", true},
+		{"p-3", "", false},
+		{"*", "", true},
+		{"", "d-1", true},
+		{"", "d-2", false},
+		{"", "*", true},
+	}
+	for _, c := range cases {
+		if result := cachedEventInScope(evt, c.ProjectID, c.DomainID); result != c.Expected {
+			t.Errorf("expected the %q project and %q domain scope to be %t, got %t", c.ProjectID, c.DomainID, c.Expected, result)
+		}
+	}
+
+	// the scope of the token is used without scope flags
+	t.Setenv("OS_PROJECT_ID", "p-3")
+	if cachedEventInScope(evt, "", "") {
+		t.Error("expected the event not to be in the scope of the token")
+	}
+	if !cachedEventInScope(evt, "p-1", "") {
+		t.Error("expected the scope flag to override the scope of the token")
+	}
+}
@@ -0,0 +1,393 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// checkpointIgnoredFlags are not restored, when an export is resumed
+var checkpointIgnoredFlags = []string{"resume", "checkpoint", "concurrency", "debug"}
+
+// exportCheckpoint records the progress of an export to resume it after an
+// interruption. The events of fetched time windows are stored next to the
+// checkpoint file.
+type exportCheckpoint struct {
+	Path string `json:"-"`
+
+	CreatedAt time.Time `json:"created_at"`
+	// Flags contains the explicitly set export flags
+	Flags    map[string]string `json:"flags"`
+	FileName string            `json:"file_name"`
+	// ListOpts contains the resolved filters, relative time flags are not
+	// evaluated again on resume
+	ListOpts events.ListOpts            `json:"list_opts"`
+	Planned  bool                       `json:"planned"`
+	Total    int                        `json:"total"`
+	Windows  []checkpointWindow         `json:"windows"`
+	Files    map[string]*checkpointFile `json:"files"`
+	// InMemory keeps the fetched events in memory only, because the events
+	// of encrypted exports must not be stored unencrypted. They are fetched
+	// again on resume.
+	InMemory bool `json:"in_memory,omitempty"`
+
+	mu           sync.Mutex
+	windowEvents map[int][]events.Event
+}
+
+// checkpointWindow is a half-open time window, which is fetched at once
+type checkpointWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Unbounded windows use the time filter of the list options
+	Unbounded bool `json:"unbounded,omitempty"`
+	Done      bool `json:"done"`
+	Events    int  `json:"events"`
+}
+
+// checkpointFile records the uploaded segments of an export file
+type checkpointFile struct {
+	Done     bool              `json:"done"`
+	Segments []UploadedSegment `json:"segments,omitempty"`
+}
+
+// getCheckpointDir returns the directory of the checkpoints, which are not
+// placed explicitly using the checkpoint flag.
+func getCheckpointDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(dir, "hermescli", "exports"), nil
+}
+
+// getChangedFlags returns the explicitly set flags, which are restored on
+// resume.
+func getChangedFlags(cmd *cobra.Command) map[string]string {
+	flags := make(map[string]string)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if !slices.Contains(checkpointIgnoredFlags, f.Name) {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	return flags
+}
+
+func newExportCheckpoint(path string, flags map[string]string, fileName string, listOpts events.ListOpts) (*exportCheckpoint, error) {
+	if path == "" {
+		dir, err := getCheckpointDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(dir, strings.ReplaceAll(fileName, "/", "_")+".checkpoint.json")
+	}
+	return &exportCheckpoint{
+		Path:      path,
+		CreatedAt: time.Now().UTC(),
+		Flags:     flags,
+		FileName:  fileName,
+		ListOpts:  listOpts,
+		Files:     make(map[string]*checkpointFile),
+	}, nil
+}
+
+func loadExportCheckpoint(path string) (*exportCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp exportCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse %s checkpoint: %w", path, err)
+	}
+	cp.Path = path
+	if cp.Files == nil {
+		cp.Files = make(map[string]*checkpointFile)
+	}
+	return &cp, nil
+}
+
+// RestoreFlags sets the flags of the interrupted export. Flags cannot be
+// changed on resume, except for the ignored ones.
+func (cp *exportCheckpoint) RestoreFlags(flags *pflag.FlagSet) error {
+	var err error
+	flags.Visit(func(f *pflag.Flag) {
+		if err == nil && !slices.Contains(checkpointIgnoredFlags, f.Name) {
+			err = fmt.Errorf("cannot combine %s flag with resume flag, the flags of the interrupted export are used", f.Name)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for name, value := range cp.Flags {
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("failed to restore %s flag: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Save writes the checkpoint file. It is safe for concurrent use.
+func (cp *exportCheckpoint) Save() error {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if err := writeFileAtomic(cp.Path, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint file and the fetched events.
+func (cp *exportCheckpoint) Remove() error {
+	if err := os.RemoveAll(cp.eventsDir()); err != nil {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	if err := os.Remove(cp.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (cp *exportCheckpoint) eventsDir() string {
+	return cp.Path + ".events"
+}
+
+func (cp *exportCheckpoint) windowPath(i int) string {
+	return filepath.Join(cp.eventsDir(), fmt.Sprintf("window-%06d.json", i))
+}
+
+// File returns the upload progress of the export file.
+func (cp *exportCheckpoint) File(name string) *checkpointFile {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	f, ok := cp.Files[name]
+	if !ok {
+		f = &checkpointFile{}
+		cp.Files[name] = f
+	}
+	return f
+}
+
+// plan splits the export into time windows, which contain less than 10000
+// events each.
+func (cp *exportCheckpoint) plan(ctx context.Context, client *gophercloud.ServiceClient, userLimit, concurrency int) error {
+	total, windows, err := planFetch(ctx, client, cp.ListOpts, userLimit, concurrency)
+	if err != nil {
+		return err
+	}
+	cp.Total = total
+
+	cp.Windows = nil
+	for _, w := range windows {
+		cp.Windows = append(cp.Windows, checkpointWindow{Start: w.Start, End: w.End})
+	}
+	if len(windows) == 0 && total > 0 {
+		// the requested events are reachable without the offset limit
+		cp.Windows = []checkpointWindow{{Unbounded: true}}
+	}
+	cp.Planned = true
+
+	return cp.Save()
+}
+
+// fetchWindow fetches the events of the window and stores them next to the
+// checkpoint.
+func (cp *exportCheckpoint) fetchWindow(ctx context.Context, client *gophercloud.ServiceClient, i, userLimit int, bar *pb.ProgressBar) error {
+	w := cp.Windows[i]
+	listOpts := cp.ListOpts
+	if !w.Unbounded {
+		listOpts = timeWindow{Start: w.Start, End: w.End}.applyTo(listOpts)
+	}
+
+	var result []events.Event
+	err := fetchPages(ctx, client, listOpts, bar, func(page []events.Event) (bool, error) {
+		result = append(result, page...)
+		// unbounded windows contain the whole export
+		return !w.Unbounded || userLimit <= 0 || len(result) < userLimit, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if cp.InMemory {
+		cp.mu.Lock()
+		if cp.windowEvents == nil {
+			cp.windowEvents = make(map[int][]events.Event)
+		}
+		cp.windowEvents[i] = result
+		cp.mu.Unlock()
+	} else {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal events: %w", err)
+		}
+		if err := writeFileAtomic(cp.windowPath(i), data); err != nil {
+			return fmt.Errorf("failed to write events: %w", err)
+		}
+	}
+
+	cp.mu.Lock()
+	cp.Windows[i].Done = true
+	cp.Windows[i].Events = len(result)
+	cp.mu.Unlock()
+
+	return cp.Save()
+}
+
+// loadWindow returns the fetched events of the window.
+func (cp *exportCheckpoint) loadWindow(i int) ([]events.Event, error) {
+	if cp.InMemory {
+		cp.mu.Lock()
+		defer cp.mu.Unlock()
+		return cp.windowEvents[i], nil
+	}
+
+	data, err := os.ReadFile(cp.windowPath(i))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	var result []events.Event
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %w", err)
+	}
+	return result, nil
+}
+
+// fetchedEvents returns the amount of events in the leading fetched windows.
+func (cp *exportCheckpoint) fetchedEvents() int {
+	var count int
+	for _, w := range cp.Windows {
+		if !w.Done {
+			break
+		}
+		count += w.Events
+	}
+	return count
+}
+
+// FetchEvents fetches the events of the windows, which were not fetched yet,
+// and returns the events of all windows in the requested order. Duplicate
+// events are removed by their ID.
+func (cp *exportCheckpoint) FetchEvents(ctx context.Context, client *gophercloud.ServiceClient, userLimit, concurrency int) ([]events.Event, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	if !cp.Planned {
+		if err := cp.plan(ctx, client, userLimit, concurrency); err != nil {
+			return nil, err
+		}
+	}
+
+	if cp.InMemory && cp.windowEvents == nil {
+		// the events of the interrupted export were not stored
+		for i := range cp.Windows {
+			cp.Windows[i].Done = false
+			cp.Windows[i].Events = 0
+		}
+	}
+
+	var pending []int
+	for i, w := range cp.Windows {
+		if !w.Done {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) < len(cp.Windows) {
+		fmt.Fprintf(os.Stderr, "Resuming with %d of %d time windows fetched\n", len(cp.Windows)-len(pending), len(cp.Windows))
+	}
+
+	// windows are fetched in time order, therefore the user limit can be
+	// applied while fetching only, when the events are sorted by time or
+	// fetched at once. Otherwise all windows are sorted before the limit is
+	// applied.
+	keys := parseSortKeys(cp.ListOpts.Sort)
+	fetchLimit := userLimit
+	if keys[0].Name != "time" && !(len(cp.Windows) == 1 && cp.Windows[0].Unbounded) {
+		fetchLimit = 0
+	}
+
+	var bar *pb.ProgressBar
+	expected := cp.Total
+	if fetchLimit > 0 && fetchLimit < expected {
+		expected = fetchLimit
+	}
+	if expected > maxOffset {
+		bar = pb.New(expected)
+		bar.SetWriter(os.Stderr)
+		bar.SetCurrent(int64(min(cp.fetchedEvents(), expected)))
+		bar.Start()
+		defer bar.Finish()
+	}
+
+	// windows are fetched in batches to stop, when the user limit is reached
+	for batch := range slices.Chunk(pending, concurrency) {
+		if fetchLimit > 0 && cp.fetchedEvents() >= fetchLimit {
+			break
+		}
+		err := runParallel(ctx, len(batch), concurrency, func(ctx context.Context, i int) error {
+			return cp.fetchWindow(ctx, client, batch[i], userLimit, bar)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var allEvents []events.Event
+	sink := &eventSink{
+		Handler: func(page []events.Event) error {
+			allEvents = append(allEvents, page...)
+			return nil
+		},
+		UserLimit: fetchLimit,
+	}
+	for i, w := range cp.Windows {
+		if !w.Done {
+			break
+		}
+		windowEvents, err := cp.loadWindow(i)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := sink.Emit(windowEvents)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+	}
+
+	if keys[0].Name != "time" {
+		// windows are ordered by time, restore the requested order
+		sortEvents(allEvents, keys)
+		if userLimit > 0 && len(allEvents) > userLimit {
+			allEvents = allEvents[:userLimit]
+		}
+	}
+
+	return allEvents, nil
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/pflag"
+)
+
+func TestExportCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.checkpoint.json")
+	listOpts := events.ListOpts{
+		Action: "create",
+		Time: []events.DateQuery{
+			{Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Filter: events.DateFilterGTE},
+		},
+	}
+
+	cp, err := newExportCheckpoint(path, map[string]string{"since": "2h", "format": "csv"}, "hermes-export", listOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.Planned = true
+	cp.Windows = []checkpointWindow{{Unbounded: true, Done: true, Events: 2}}
+	cp.File("hermes-export.csv").Segments = []UploadedSegment{{Name: "hermes-export-segments/0000000000000001", SizeBytes: 4, Etag: "abc"}}
+	if err := cp.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadExportCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.FileName != "hermes-export" || loaded.ListOpts.Action != "create" || !loaded.ListOpts.Time[0].Date.Equal(listOpts.Time[0].Date) {
+		t.Errorf("unexpected checkpoint: %+v", loaded)
+	}
+	if loaded.fetchedEvents() != 2 {
+		t.Errorf("expected 2 fetched events, got %d", loaded.fetchedEvents())
+	}
+	if segments := loaded.File("hermes-export.csv").Segments; len(segments) != 1 || segments[0].Etag != "abc" {
+		t.Errorf("unexpected segments: %+v", segments)
+	}
+
+	newFlags := func() *pflag.FlagSet {
+		flags := pflag.NewFlagSet("export", pflag.ContinueOnError)
+		flags.String("since", "", "")
+		flags.String("format", "json", "")
+		flags.String("resume", "", "")
+		flags.Uint("concurrency", 4, "")
+		return flags
+	}
+
+	flags := newFlags()
+	if err := flags.Parse([]string{"--resume", path, "--concurrency", "8"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.RestoreFlags(flags); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := flags.GetString("format"); err != nil || v != "csv" {
+		t.Errorf("expected the format flag to be restored, got %q", v)
+	}
+
+	// flags of the interrupted export cannot be changed
+	flags = newFlags()
+	if err := flags.Parse([]string{"--resume", path, "--format", "yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.RestoreFlags(flags); err == nil {
+		t.Error("expected changed flags to fail")
+	}
+
+	if err := os.MkdirAll(loaded.eventsDir(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed, got %v", err)
+	}
+}
+
+func TestExportCheckpointSortedLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.checkpoint.json")
+	cp, err := newExportCheckpoint(path, nil, "hermes-export", events.ListOpts{Sort: "initiator_name:asc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// windows are ordered by time, the first names are in the second window
+	windows := [][]events.Event{
+		{{ID: "1", Initiator: cadf.Resource{Name: "dave"}}, {ID: "2", Initiator: cadf.Resource{Name: "carol"}}},
+		{{ID: "3", Initiator: cadf.Resource{Name: "bob"}}, {ID: "4", Initiator: cadf.Resource{Name: "alice"}}},
+	}
+	cp.Planned = true
+	if err := os.MkdirAll(cp.eventsDir(), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	for i, windowEvents := range windows {
+		data, err := json.Marshal(windowEvents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(cp.windowPath(i), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cp.Windows = append(cp.Windows, checkpointWindow{Done: true, Events: len(windowEvents)})
+	}
+
+	allEvents, err := cp.FetchEvents(context.Background(), nil, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, evt := range allEvents {
+		names = append(names, evt.Initiator.Name)
+	}
+	if expected := []string{"alice", "bob"}; !slices.Equal(names, expected) {
+		t.Errorf("expected the first events %v in the requested order, got %v", expected, names)
+	}
+}
+
+func TestExportCheckpointInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.checkpoint.json")
+	cp, err := newExportCheckpoint(path, nil, "hermes-export", events.ListOpts{Sort: "time:asc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.InMemory = true
+	cp.Planned = true
+	cp.Windows = []checkpointWindow{{Done: true, Events: 1}}
+	cp.windowEvents = map[int][]events.Event{0: {{ID: "1"}}}
+	if err := cp.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	allEvents, err := cp.FetchEvents(context.Background(), nil, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allEvents) != 1 || allEvents[0].ID != "1" {
+		t.Errorf("expected the events kept in memory, got %v", allEvents)
+	}
+	if _, err := os.Stat(cp.eventsDir()); !os.IsNotExist(err) {
+		t.Errorf("expected no stored events, got %v", err)
+	}
+
+	// the events are fetched again on resume
+	loaded, err := loadExportCheckpoint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.InMemory || loaded.windowEvents != nil {
+		t.Errorf("expected an in-memory checkpoint without events, got %+v", loaded)
+	}
+}
@@ -0,0 +1,237 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.xyrillian.de/schwift/v2"
+)
+
+// segmentsSuffix separates the export file name from the segment index in
+// segment object names
+const segmentsSuffix = "-segments/"
+
+// exportFileExtensions returns the extensions of export files with all
+// formats, compressions and encryptions and of their signatures
+func exportFileExtensions() []string {
+	result := []string{"." + string(signatureFormat)}
+	for _, format := range allExportFormats {
+		for _, c := range append([]Compression{CompressionNone}, allCompressions...) {
+			ext := "." + string(format) + c.Extension()
+			result = append(result, ext, ext+encryptionExtension)
+		}
+	}
+	return result
+}
+
+// findOrphanSegments returns the segments below the prefix, which were
+// uploaded by hermescli, modified before the cutoff and are not referenced by
+// an export file. Such segments are left behind by interrupted exports, which
+// were not resumed.
+func findOrphanSegments(ctx context.Context, container *schwift.Container, prefix string, cutoff time.Time) ([]schwift.ObjectInfo, error) {
+	iter := container.Objects()
+	iter.Prefix = prefix
+	infos, err := iter.CollectDetailed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	exists := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		exists[info.Object.Name()] = true
+	}
+
+	// referenced returns the segments referenced by the export files of the
+	// file name
+	referencedByFile := make(map[string]map[string]bool)
+	referenced := func(fileName string) (map[string]bool, error) {
+		if result, ok := referencedByFile[fileName]; ok {
+			return result, nil
+		}
+		result := make(map[string]bool)
+		for _, ext := range exportFileExtensions() {
+			if !exists[fileName+ext] {
+				continue
+			}
+			lo, err := container.Object(fileName + ext).AsLargeObject(ctx)
+			if errors.Is(err, schwift.ErrNotLarge) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the %s manifest: %w", fileName+ext, err)
+			}
+			for _, obj := range lo.SegmentObjects() {
+				result[obj.Name()] = true
+			}
+		}
+		referencedByFile[fileName] = result
+		return result, nil
+	}
+
+	var result []schwift.ObjectInfo
+	for _, info := range infos {
+		name := info.Object.Name()
+		idx := strings.LastIndex(name, segmentsSuffix)
+		if idx < 0 || !info.LastModified.Before(cutoff) {
+			continue
+		}
+		refs, err := referenced(name[:idx])
+		if err != nil {
+			return nil, err
+		}
+		if refs[name] {
+			continue
+		}
+		// segments of other tools may share the container and the naming
+		marked, err := hasExportMarker(ctx, info.Object)
+		if err != nil {
+			return nil, err
+		}
+		if marked {
+			result = append(result, info)
+		}
+	}
+
+	return result, nil
+}
+
+// findAbandonedCheckpoints returns the checkpoints in the default checkpoint
+// directory, which were modified before the cutoff.
+func findAbandonedCheckpoints(cutoff time.Time) ([]string, error) {
+	dir, err := getCheckpointDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	var result []string
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".checkpoint.json") {
+			continue
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect checkpoint: %w", err)
+		}
+		if fi.ModTime().Before(cutoff) {
+			result = append(result, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return result, nil
+}
+
+// ExportCleanupCmd represents the export cleanup command
+var ExportCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Args:  cobra.NoArgs,
+	Short: "Remove leftovers of abandoned exports",
+	Long: `Remove segments of interrupted exports, which were not resumed, from a Swift container and
+remove the local checkpoints of such exports. Only leftovers older than --older-than are removed to
+keep exports, which are still running or may be resumed. Segments, which were not uploaded by
+hermescli, are kept.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		if viper.GetString("output") != "" || viper.GetString("container") != "" {
+			spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+			if err != nil {
+				return err
+			}
+			if spec.Kind != destinationSwift {
+				return errors.New("only Swift destinations contain segments")
+			}
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		dryRun := viper.GetBool("dry-run")
+		verb := "Removed"
+		if dryRun {
+			verb = "Found"
+		}
+
+		cutoff, err := parseTimeExpr(viper.GetString("older-than"), time.Now(), timezone)
+		if err != nil {
+			return fmt.Errorf("failed to parse older-than: %w", err)
+		}
+
+		if viper.GetString("output") != "" || viper.GetString("container") != "" {
+			spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+			if err != nil {
+				return err
+			}
+			client, err := NewHermesV1Client(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create Hermes client: %w", err)
+			}
+			container, err := InitializeSwiftContainer(ctx, client.ProviderClient, spec.Container)
+			if err != nil {
+				return fmt.Errorf("failed to initialize Swift container: %w", err)
+			}
+
+			orphans, err := findOrphanSegments(ctx, container, spec.Prefix, cutoff)
+			if err != nil {
+				return err
+			}
+			var size uint64
+			objects := make([]*schwift.Object, 0, len(orphans))
+			for _, info := range orphans {
+				size += info.SizeBytes
+				objects = append(objects, info.Object)
+				if dryRun {
+					fmt.Println(info.Object.FullName())
+				}
+			}
+			if !dryRun && len(objects) > 0 {
+				if _, _, err := container.Account().BulkDelete(ctx, objects, nil, nil); err != nil {
+					return fmt.Errorf("failed to delete segments: %w", err)
+				}
+			}
+			fmt.Fprintf(os.Stderr, "%s %d orphan segments with %.1fMB\n", verb, len(objects), float64(size)/1024/1024)
+		}
+
+		checkpoints, err := findAbandonedCheckpoints(cutoff)
+		if err != nil {
+			return err
+		}
+		for _, path := range checkpoints {
+			if dryRun {
+				fmt.Println(path)
+				continue
+			}
+			if err := (&exportCheckpoint{Path: path}).Remove(); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%s %d abandoned checkpoints\n", verb, len(checkpoints))
+
+		return nil
+	},
+}
+
+func init() {
+	ExportCleanupCmd.Flags().StringP("output", "o", "", "Swift destination of the exports: swift://container/prefix")
+	ExportCleanupCmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
+	ExportCleanupCmd.Flags().StringP("older-than", "", "1d", "remove leftovers modified before this time, e.g. 7d or 2024-05-01")
+	ExportCleanupCmd.Flags().Bool("dry-run", false, "print the leftovers instead of removing them")
+	ExportCmd.AddCommand(ExportCleanupCmd)
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFindOrphanSegments(t *testing.T) {
+	cutoff := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	old := cutoff.Add(-time.Hour)
+	marked := map[string]string{exportMarkerHeader: "true"}
+
+	container := newFakeSwiftContainer(t, map[string]fakeSwiftObject{
+		"hermes-export.json": {
+			Headers:      marked,
+			Segments:     []string{"hermes-export-segments/0000000000000001"},
+			LastModified: old,
+		},
+		"hermes-export-segments/0000000000000001": {Headers: marked, LastModified: old},
+		// left behind by an interrupted upload of the same file name
+		"hermes-export-segments/0000000000000002": {Headers: marked, LastModified: old},
+		"interrupted-segments/0000000000000001":   {Headers: marked, LastModified: old},
+		// the export may still be running or resumed
+		"interrupted-segments/0000000000000002": {Headers: marked, LastModified: cutoff.Add(time.Hour)},
+		// foreign segments share the container and the naming
+		"hermes-export-segments/0000000000000003": {LastModified: old},
+		"backup.tar-segments/0000000000000001":    {LastModified: old},
+		"backup.json": {
+			Segments:     []string{"backup.json-segments/0000000000000001"},
+			LastModified: old,
+		},
+		"backup.json-segments/0000000000000001": {LastModified: old},
+		"backup.json-segments/0000000000000002": {LastModified: old},
+	})
+
+	orphans, err := findOrphanSegments(context.Background(), container, "", cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	for _, info := range orphans {
+		result = append(result, info.Object.Name())
+	}
+	expected := []string{
+		"hermes-export-segments/0000000000000002",
+		"interrupted-segments/0000000000000001",
+	}
+	if !slices.Equal(result, expected) {
+		t.Errorf("expected the orphan segments %v, got %v", expected, result)
+	}
+}
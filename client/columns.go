@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+)
+
+// maxFieldPathDepth limits the depth of the completed field paths
+const maxFieldPathDepth = 4
+
+var fieldPathPartRx = regexp.MustCompile(`^([^\[\]]+)((?:\[\d+\])*)$`)
+var fieldPathIndexRx = regexp.MustCompile(`\[(\d+)\]`)
+
+// eventKVKeys are the columns of eventToKV
+var eventKVKeys = append(slices.Clone(defaultShowKeyOrder), "Source")
+
+// fieldPathElem is a field name, a JSON object key or an index of a field
+// path.
+type fieldPathElem struct {
+	Name    string
+	Index   int
+	IsIndex bool
+}
+
+// parseFieldPath parses a dot separated field path into its elements, e.g.
+// "Attachments[0].Content.name".
+func parseFieldPath(path string) ([]fieldPathElem, error) {
+	var result []fieldPathElem
+	for part := range strings.SplitSeq(path, ".") {
+		m := fieldPathPartRx.FindStringSubmatch(part)
+		if m == nil {
+			return nil, fmt.Errorf("invalid %q element of the %q field path", part, path)
+		}
+		result = append(result, fieldPathElem{Name: m[1]})
+		for _, idx := range fieldPathIndexRx.FindAllStringSubmatch(m[2], -1) {
+			i, err := strconv.Atoi(idx[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q index of the %q field path: %w", idx[1], path, err)
+			}
+			result = append(result, fieldPathElem{Index: i, IsIndex: true})
+		}
+	}
+	return result, nil
+}
+
+// findStructField returns the exported struct field with the name. The
+// field name is matched case-insensitively, JSON names are supported too.
+func findStructField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if strings.EqualFold(f.Name, name) || (jsonName != "" && jsonName == name) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// verifyFieldPath verifies that the field path exists in the event
+// structure. Paths into attachment contents are verified up to the content,
+// because the content is arbitrary JSON.
+func verifyFieldPath(path string) error {
+	elems, err := parseFieldPath(path)
+	if err != nil {
+		return err
+	}
+
+	t := reflect.TypeFor[events.Event]()
+	for _, e := range elems {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch {
+		case t.Kind() == reflect.Interface:
+			return nil
+		case e.IsIndex:
+			if t.Kind() != reflect.Slice {
+				return fmt.Errorf("cannot index a %s in the %q field path", t.Kind(), path)
+			}
+			t = t.Elem()
+		case t.Kind() != reflect.Struct:
+			return fmt.Errorf("the %q field path has no %q field", path, e.Name)
+		default:
+			f, ok := findStructField(t, e.Name)
+			if !ok {
+				return fmt.Errorf("the %q field path has no %q field", path, e.Name)
+			}
+			t = f.Type
+		}
+	}
+	return nil
+}
+
+// lookupJSON returns the value of the path elements in a generic JSON value.
+// Strings containing JSON are decoded.
+func lookupJSON(v any, elems []fieldPathElem) (any, bool) {
+	for _, e := range elems {
+		if s, ok := v.(string); ok {
+			var parsed any
+			if err := json.Unmarshal([]byte(s), &parsed); err == nil {
+				v = parsed
+			}
+		}
+		if e.IsIndex {
+			items, ok := v.([]any)
+			if !ok || e.Index >= len(items) {
+				return nil, false
+			}
+			v = items[e.Index]
+			continue
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[e.Name]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// formatFieldValue formats scalar values as text and other values as JSON.
+func formatFieldValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// resolveFieldPath returns the value of the field path in the event. It
+// returns false, when the path does not exist in the event, e.g. because of
+// a nil pointer or an index out of range.
+func resolveFieldPath(evt events.Event, path string) (string, bool) {
+	elems, err := parseFieldPath(path)
+	if err != nil {
+		return "", false
+	}
+
+	rv := reflect.ValueOf(evt)
+	for i, e := range elems {
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return "", false
+			}
+			rv = rv.Elem()
+		}
+		switch {
+		case rv.Kind() == reflect.Interface:
+			if rv.IsNil() {
+				return "", false
+			}
+			v, ok := lookupJSON(rv.Interface(), elems[i:])
+			return formatFieldValue(v), ok
+		case e.IsIndex:
+			if rv.Kind() != reflect.Slice || e.Index >= rv.Len() {
+				return "", false
+			}
+			rv = rv.Index(e.Index)
+		case rv.Kind() != reflect.Struct:
+			return "", false
+		default:
+			f, ok := findStructField(rv.Type(), e.Name)
+			if !ok {
+				return "", false
+			}
+			rv = rv.FieldByIndex(f.Index)
+		}
+	}
+
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return "", false
+		}
+		rv = rv.Elem()
+	}
+	return formatFieldValue(rv.Interface()), true
+}
+
+// eventColumnValue returns the value of a column, which is either a key of
+// eventToKV or a field path.
+func eventColumnValue(evt events.Event, kv map[string]string, column string) (string, bool) {
+	if slices.Contains(eventKVKeys, column) {
+		v, ok := kv[column]
+		return v, ok
+	}
+	return resolveFieldPath(evt, column)
+}
+
+// appendFieldPaths appends the field paths of the type to the result.
+// Slices are completed with the first index.
+func appendFieldPaths(result []string, prefix string, t reflect.Type, depth int) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if depth >= maxFieldPathDepth {
+			return result
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			path := f.Name
+			if prefix != "" {
+				path = prefix + "." + f.Name
+			}
+			result = append(result, path)
+			result = appendFieldPaths(result, path, f.Type, depth+1)
+		}
+	case reflect.Slice:
+		path := prefix + "[0]"
+		result = append(result, path)
+		result = appendFieldPaths(result, path, t.Elem(), depth)
+	}
+	return result
+}
+
+// eventFieldPaths returns the field paths of the event structure.
+func eventFieldPaths() []string {
+	return appendFieldPaths(nil, "", reflect.TypeFor[events.Event](), 0)
+}
+
+// completeColumns completes the comma separated columns with the keys of
+// eventToKV and the field paths of the event structure.
+func completeColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var prefix, current string
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, current = toComplete[:i+1], toComplete[i+1:]
+	} else {
+		current = toComplete
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, c := range slices.Concat(eventKVKeys, eventFieldPaths()) {
+		if strings.HasPrefix(c, current) && !seen[c] {
+			seen[c] = true
+			result = append(result, prefix+c)
+		}
+	}
+	return result, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestVerifyFieldPath(t *testing.T) {
+	for _, path := range []string{"Initiator.ProjectID", "Target.Name", "Reason.ReasonCode", "Initiator.Host.Platform", "Observer.ID", "Attachments[0].Content.name[1].id", "initiator.project_id"} {
+		if err := verifyFieldPath(path); err != nil {
+			t.Errorf("expected %q to be valid: %s", path, err)
+		}
+	}
+	for _, path := range []string{"", "Initiator.", "Initiator.Nope", "Action.Name", "Target[0]", "Attachments[x]", "Attachments.[0]"} {
+		if err := verifyFieldPath(path); err == nil {
+			t.Errorf("expected %q to fail", path)
+		}
+	}
+}
+
+func TestResolveFieldPath(t *testing.T) {
+	evt := events.Event{
+		ID:        "1",
+		Initiator: cadf.Resource{ProjectID: "p-1", Host: &cadf.Host{Platform: "linux"}},
+		Reason:    cadf.Reason{ReasonCode: "200"},
+		Attachments: []cadf.Attachment{
+			{Name: "payload", Content: `{"name":"vm","ports":[{"id":"port-1"}],"size":2}`},
+			{Name: "raw", Content: map[string]any{"enabled": true}},
+		},
+	}
+
+	cases := []struct {
+		Path     string
+		Expected string
+		OK       bool
+	}{
+		{"Initiator.ProjectID", "p-1", true},
+		{"Initiator.Host.Platform", "linux", true},
+		{"Reason.ReasonCode", "200", true},
+		{"Target.Host.Platform", "", false},
+		{"Attachments[0].Name", "payload", true},
+		{"Attachments[0].Content.ports[0].id", "port-1", true},
+		{"Attachments[0].Content.size", "2", true},
+		{"Attachments[0].Content.ports", `[{"id":"port-1"}]`, true},
+		{"Attachments[1].Content.enabled", "true", true},
+		{"Attachments[2].Name", "", false},
+		{"Attachments[0].Content.missing", "", false},
+	}
+	for _, c := range cases {
+		result, ok := resolveFieldPath(evt, c.Path)
+		if result != c.Expected || ok != c.OK {
+			t.Errorf("expected %q to be resolved to %q (%t), got %q (%t)", c.Path, c.Expected, c.OK, result, ok)
+		}
+	}
+
+	if row := eventToRow(evt, []string{"ID", "Initiator.ProjectID", "Observer"}); !slices.Equal(row, []string{"1", "p-1", ""}) {
+		t.Errorf("unexpected row: %v", row)
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+var (
+	allCompressions = []Compression{
+		CompressionGzip,
+		CompressionZstd,
+	}
+)
+
+func parseCompression(input string) (Compression, error) {
+	if input == "" || input == "none" {
+		return CompressionNone, nil
+	}
+	if slices.Contains(allCompressions, Compression(input)) {
+		return Compression(input), nil
+	}
+	return "", fmt.Errorf("unsupported compression: %s (supported compressions: %v)", input, allCompressions)
+}
+
+// Extension returns the file name extension including the leading dot
+func (c Compression) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return ".gz"
+	case CompressionZstd:
+		return ".zst"
+	default:
+		return ""
+	}
+}
+
+// ContentEncoding returns the value of the Content-Encoding header
+func (c Compression) ContentEncoding() string {
+	return string(c)
+}
+
+// newCompressWriter returns a writer, which compresses into w. The writer must
+// be closed to flush the compressed stream.
+func newCompressWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case CompressionGzip:
+		return gzip.NewWriter(w), nil
+	case CompressionZstd:
+		return zstd.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", c)
+	}
+}
+
+// newDecompressReader returns a reader of the decompressed r.
+func newDecompressReader(r io.Reader, c Compression) (io.ReadCloser, error) {
+	switch c {
+	case CompressionNone:
+		return io.NopCloser(r), nil
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", c)
+	}
+}
+
+// compressReader returns a reader of the compressed r. Compression runs in
+// the background while the result is read. The result must be closed to stop
+// the compression, when it is not read till the end.
+func compressReader(r io.Reader, c Compression) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		w, err := newCompressWriter(pw, c)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			w.Close() //nolint:errcheck
+			pw.CloseWithError(fmt.Errorf("failed to compress: %w", err))
+			return
+		}
+		pw.CloseWithError(w.Close())
+	}()
+	return pr
+}
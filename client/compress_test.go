@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressReader(t *testing.T) {
+	input := strings.Repeat(`{"id":"7be6c4ff-b761-5f1f-b234-f5d41616c2cd"}`, 1000)
+
+	for _, c := range allCompressions {
+		compressed, err := io.ReadAll(compressReader(strings.NewReader(input), c))
+		if err != nil {
+			t.Fatalf("failed to compress using %s: %s", c, err)
+		}
+		if len(compressed) >= len(input) {
+			t.Errorf("expected %s to compress the input, got %d bytes", c, len(compressed))
+		}
+
+		var r io.Reader
+		switch c {
+		case CompressionGzip:
+			r, err = gzip.NewReader(bytes.NewReader(compressed))
+		case CompressionZstd:
+			r, err = zstd.NewReader(bytes.NewReader(compressed))
+		}
+		if err != nil {
+			t.Fatalf("failed to decompress %s: %s", c, err)
+		}
+		output, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to decompress %s: %s", c, err)
+		}
+		if string(output) != input {
+			t.Errorf("unexpected %s round trip result", c)
+		}
+	}
+}
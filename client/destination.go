@@ -0,0 +1,326 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"go.xyrillian.de/schwift/v2"
+)
+
+// exportDestination stores export files, e.g. in a Swift container or in a
+// local directory.
+type exportDestination interface {
+	// Upload stores the export file
+	Upload(ctx context.Context, f ExportFile) error
+	// Location returns a human readable location of the export file
+	Location(f ExportFile) string
+	// Open returns the contents of a previously stored file
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the stored files, whose names start with the prefix
+	List(ctx context.Context, prefix string) ([]exportObject, error)
+	// StateStore returns the store of the incremental export state next to
+	// the export files or nil, when the destination cannot store state
+	StateStore(name string) exportStateStore
+}
+
+// exportObject is a file stored in an export destination
+type exportObject struct {
+	// Name is relative to the destination prefix
+	Name         string
+	SizeBytes    uint64
+	LastModified time.Time
+}
+
+// isNotFound returns true, when a file does not exist in the destination
+func isNotFound(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || schwift.Is(err, http.StatusNotFound)
+}
+
+// destinationKind is the kind of an export destination
+type destinationKind string
+
+const (
+	destinationSwift  destinationKind = "swift"
+	destinationFile   destinationKind = "file"
+	destinationStdout destinationKind = "stdout"
+)
+
+// destinationSpec is a parsed --output flag value
+type destinationSpec struct {
+	Kind destinationKind
+	// Container is the Swift container name
+	Container string
+	// Prefix is the object name prefix in Swift or the local directory
+	Prefix string
+}
+
+// parseDestination parses export destinations in the following forms:
+//
+//   - "swift://container/prefix" for a Swift container
+//   - "file:///path/to/directory" for a local directory
+//   - "-" for the standard output
+func parseDestination(output string) (destinationSpec, error) {
+	if output == "-" {
+		return destinationSpec{Kind: destinationStdout}, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return destinationSpec{}, fmt.Errorf("invalid %q output: %w", output, err)
+	}
+
+	switch u.Scheme {
+	case "swift":
+		if u.Host == "" {
+			return destinationSpec{}, fmt.Errorf(`invalid %q output: missing container name, expected "swift://container/prefix"`, output)
+		}
+		prefix := strings.TrimPrefix(u.Path, "/")
+		if prefix != "" && !strings.HasSuffix(prefix, "/") {
+			prefix += "/"
+		}
+		return destinationSpec{Kind: destinationSwift, Container: u.Host, Prefix: prefix}, nil
+	case "file":
+		// support relative paths like "file://exports"
+		dir := u.Host + u.Path
+		if dir == "" {
+			return destinationSpec{}, fmt.Errorf(`invalid %q output: missing directory, expected "file:///path/to/directory"`, output)
+		}
+		return destinationSpec{Kind: destinationFile, Prefix: filepath.FromSlash(dir)}, nil
+	}
+
+	return destinationSpec{}, fmt.Errorf(`unsupported %q output, supported outputs are "swift://container/prefix", "file:///path/to/directory" and "-"`, output)
+}
+
+// newExportDestination creates the destination of the spec. The provider is
+// required for Swift destinations only.
+func newExportDestination(ctx context.Context, provider *gophercloud.ProviderClient, spec destinationSpec) (exportDestination, error) {
+	switch spec.Kind {
+	case destinationSwift:
+		container, err := InitializeSwiftContainer(ctx, provider, spec.Container)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Swift container: %w", err)
+		}
+		return swiftDestination{Container: container, Prefix: spec.Prefix}, nil
+	case destinationFile:
+		return fileDestination{Dir: spec.Prefix}, nil
+	case destinationStdout:
+		return stdoutDestination{Writer: os.Stdout}, nil
+	}
+	return nil, fmt.Errorf("unsupported %q destination", spec.Kind)
+}
+
+// swiftDestination uploads export files as static large objects
+type swiftDestination struct {
+	Container *schwift.Container
+	Prefix    string
+}
+
+func (d swiftDestination) Upload(ctx context.Context, f ExportFile) error {
+	f.FileName = d.Prefix + f.FileName
+	if err := f.UploadTo(ctx, d.Container); err != nil {
+		return fmt.Errorf("failed to upload to Swift: %w", err)
+	}
+	return nil
+}
+
+func (d swiftDestination) Location(f ExportFile) string {
+	return path.Join(d.Container.Name(), d.Prefix+f.ObjectName())
+}
+
+func (d swiftDestination) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	// the stored bytes are requested explicitly, otherwise gzip encoded
+	// objects are decompressed transparently by the HTTP client
+	headers := make(schwift.Headers)
+	headers.Set("Accept-Encoding", "identity")
+	r, err := d.Container.Object(d.Prefix+name).Download(ctx, headers.ToOpts()).AsReadCloser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	return r, nil
+}
+
+func (d swiftDestination) List(ctx context.Context, prefix string) ([]exportObject, error) {
+	iter := d.Container.Objects()
+	iter.Prefix = d.Prefix + prefix
+	infos, err := iter.CollectDetailed(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	result := make([]exportObject, 0, len(infos))
+	for _, info := range infos {
+		result = append(result, exportObject{
+			Name:         strings.TrimPrefix(info.Object.Name(), d.Prefix),
+			SizeBytes:    info.SizeBytes,
+			LastModified: info.LastModified,
+		})
+	}
+	return result, nil
+}
+
+func (d swiftDestination) StateStore(name string) exportStateStore {
+	return swiftStateStore{Object: d.Container.Object(d.Prefix + name)}
+}
+
+// fileDestination writes export files into a local directory
+type fileDestination struct {
+	Dir string
+}
+
+func (d fileDestination) Upload(ctx context.Context, f ExportFile) error {
+	filename := filepath.Join(d.Dir, filepath.FromSlash(f.ObjectName()))
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// write into a temporary file first to never leave partial exports behind
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	if _, err := io.Copy(tmp, f.Contents); err != nil {
+		tmp.Close() //nolint:errcheck
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+func (d fileDestination) Location(f ExportFile) string {
+	return filepath.Join(d.Dir, filepath.FromSlash(f.ObjectName()))
+}
+
+func (d fileDestination) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.Dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	return f, nil
+}
+
+func (d fileDestination) List(ctx context.Context, prefix string) ([]exportObject, error) {
+	var result []exportObject
+	err := filepath.WalkDir(d.Dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == d.Dir && errors.Is(err, fs.ErrNotExist) {
+				// nothing was exported yet
+				return filepath.SkipAll
+			}
+			return err
+		}
+		// skip temporary files of interrupted writes
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			return nil
+		}
+		fi, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(d.Dir, p)
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(filepath.ToSlash(rel), prefix) {
+			return nil
+		}
+		result = append(result, exportObject{
+			Name:         filepath.ToSlash(rel),
+			SizeBytes:    uint64(fi.Size()), //nolint:gosec // file sizes are not negative
+			LastModified: fi.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	return result, nil
+}
+
+func (d fileDestination) StateStore(name string) exportStateStore {
+	return fileStateStore{Path: filepath.Join(d.Dir, name)}
+}
+
+// stdoutDestination writes export files to the standard output
+type stdoutDestination struct {
+	Writer io.Writer
+}
+
+func (d stdoutDestination) Upload(ctx context.Context, f ExportFile) error {
+	if _, err := io.Copy(d.Writer, f.Contents); err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+	return nil
+}
+
+func (d stdoutDestination) Location(f ExportFile) string {
+	return "stdout"
+}
+
+func (d stdoutDestination) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return nil, errors.New("stdout cannot be read")
+}
+
+func (d stdoutDestination) List(ctx context.Context, prefix string) ([]exportObject, error) {
+	return nil, errors.New("stdout cannot be listed")
+}
+
+func (d stdoutDestination) StateStore(name string) exportStateStore {
+	return nil
+}
+
+// openExportLocation returns the destination of existing exports to read them.
+// The Hermes client is only created for Swift destinations.
+func openExportLocation(ctx context.Context, output, container string) (exportDestination, error) {
+	spec, err := getExportDestination(output, container)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Kind == destinationStdout {
+		return nil, errors.New("exports to stdout cannot be read")
+	}
+
+	var provider *gophercloud.ProviderClient
+	if spec.Kind == destinationSwift {
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+		provider = client.ProviderClient
+	}
+	return newExportDestination(ctx, provider, spec)
+}
+
+// getExportDestination returns the destination spec from the output and the
+// deprecated container flags.
+func getExportDestination(output, container string) (destinationSpec, error) {
+	switch {
+	case output != "" && container != "":
+		return destinationSpec{}, errors.New("--output and --container cannot be both specified")
+	case container != "":
+		return destinationSpec{Kind: destinationSwift, Container: container}, nil
+	case output != "":
+		return parseDestination(output)
+	}
+	return destinationSpec{}, errors.New("export destination is required, use --output or --container")
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseDestination(t *testing.T) {
+	cases := []struct {
+		Output   string
+		Expected destinationSpec
+	}{
+		{"-", destinationSpec{Kind: destinationStdout}},
+		{"swift://audit", destinationSpec{Kind: destinationSwift, Container: "audit"}},
+		{"swift://audit/", destinationSpec{Kind: destinationSwift, Container: "audit"}},
+		{"swift://audit/2024/05", destinationSpec{Kind: destinationSwift, Container: "audit", Prefix: "2024/05/"}},
+		{"file:///tmp/exports", destinationSpec{Kind: destinationFile, Prefix: filepath.FromSlash("/tmp/exports")}},
+		{"file://exports", destinationSpec{Kind: destinationFile, Prefix: "exports"}},
+	}
+
+	for _, c := range cases {
+		result, err := parseDestination(c.Output)
+		if err != nil {
+			t.Errorf("failed to parse %q: %s", c.Output, err)
+			continue
+		}
+		if result != c.Expected {
+			t.Errorf("expected %q to be parsed as %+v, got %+v", c.Output, c.Expected, result)
+		}
+	}
+
+	for _, output := range []string{"", "swift://", "file://", "s3://bucket", "/tmp/exports"} {
+		if _, err := parseDestination(output); err == nil {
+			t.Errorf("expected %q to fail", output)
+		}
+	}
+}
+
+func TestFileDestination(t *testing.T) {
+	dir := t.TempDir()
+	d := fileDestination{Dir: dir}
+	f := ExportFile{
+		Format:   ExportFormatJSON,
+		FileName: "hermes-export",
+		Contents: strings.NewReader(`[]`),
+	}
+
+	if err := d.Upload(context.Background(), f); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "hermes-export.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `[]` {
+		t.Errorf("unexpected file contents: %q", data)
+	}
+	if loc := d.Location(f); loc != filepath.Join(dir, "hermes-export.json") {
+		t.Errorf("unexpected location: %s", loc)
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// exportFileReader reads the plain contents of an export file
+type exportFileReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r exportFileReader) Close() error {
+	var errs []error
+	for _, c := range r.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// exportFileType describes an export file by the extensions of its name
+type exportFileType struct {
+	Format      ExportFormat
+	Compression Compression
+	Encrypted   bool
+}
+
+// parseExportFileType returns the type of the export file. The result is
+// false, when the name has no export format extension.
+func parseExportFileType(objectName string) (exportFileType, bool) {
+	var result exportFileType
+	name, encrypted := strings.CutSuffix(objectName, encryptionExtension)
+	result.Encrypted = encrypted
+	for _, c := range allCompressions {
+		if trimmed, ok := strings.CutSuffix(name, c.Extension()); ok {
+			name = trimmed
+			result.Compression = c
+			break
+		}
+	}
+	for _, format := range allExportFormats {
+		if strings.HasSuffix(name, "."+string(format)) {
+			result.Format = format
+			return result, true
+		}
+	}
+	return result, false
+}
+
+// openExportFile returns the decrypted and decompressed contents of the
+// export file. The identities are required for encrypted files.
+func openExportFile(ctx context.Context, destination exportDestination, objectName string, identities []age.Identity) (io.ReadCloser, error) {
+	r, err := destination.Open(ctx, objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	fileType, _ := parseExportFileType(objectName)
+	var decrypted io.Reader = r
+	if fileType.Encrypted {
+		decrypted, err = decryptReader(r, identities)
+		if err != nil {
+			r.Close() //nolint:errcheck
+			return nil, fmt.Errorf("failed to read %s: %w", objectName, err)
+		}
+	}
+	decompressed, err := newDecompressReader(decrypted, fileType.Compression)
+	if err != nil {
+		r.Close() //nolint:errcheck
+		return nil, fmt.Errorf("failed to decompress %s: %w", objectName, err)
+	}
+
+	return exportFileReader{Reader: decompressed, closers: []io.Closer{decompressed, r}}, nil
+}
+
+// DownloadCmd represents the download command
+var DownloadCmd = &cobra.Command{
+	Use:   "download <object-name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Download an exported file",
+	Long: `Download an exported file and write its decrypted and decompressed contents to stdout or to a
+local file. Encrypted files require the age identity of one of the recipients or the passphrase.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		if spec.Kind == destinationStdout {
+			return errors.New("exports to stdout cannot be downloaded")
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		destination, err := openExportLocation(ctx, viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+
+		var identities []age.Identity
+		if fileType, _ := parseExportFileType(args[0]); fileType.Encrypted {
+			identities, err = loadIdentities(viper.GetStringSlice("identity"), viper.GetString("passphrase-cmd"))
+			if err != nil {
+				return err
+			}
+		}
+
+		r, err := openExportFile(ctx, destination, args[0], identities)
+		if err != nil {
+			return err
+		}
+		defer r.Close() //nolint:errcheck
+
+		to := viper.GetString("to")
+		if to == "" || to == "-" {
+			if _, err := io.Copy(os.Stdout, r); err != nil {
+				return fmt.Errorf("failed to download %s: %w", args[0], err)
+			}
+			return nil
+		}
+
+		f, err := os.Create(to)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close() //nolint:errcheck
+			return fmt.Errorf("failed to download %s: %w", args[0], err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Downloaded %s to %s\n", args[0], to)
+		return nil
+	},
+}
+
+func init() {
+	DownloadCmd.Flags().StringP("output", "o", "", "location of the export: swift://container/prefix or file:///path/to/directory")
+	DownloadCmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
+	DownloadCmd.Flags().StringSlice("identity", nil, "age identity file to decrypt encrypted exports, can be specified multiple times")
+	DownloadCmd.Flags().String("passphrase-cmd", "", "command, which prints the passphrase to decrypt encrypted exports")
+	DownloadCmd.Flags().String("to", "", "local file to write the contents to (default: stdout)")
+	RootCmd.AddCommand(DownloadCmd)
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// encryptionExtension is appended to the object name of encrypted export files
+const encryptionExtension = ".age"
+
+// passphraseRecipient is recorded instead of the recipients, when the export
+// is encrypted with a passphrase
+const passphraseRecipient = "passphrase"
+
+// exportEncryption encrypts export files for the recipients
+type exportEncryption struct {
+	Recipients []age.Recipient
+	// Names are the recipients recorded in the object metadata and in the
+	// integrity manifest
+	Names []string
+}
+
+// readPassphrase returns the output of the passphrase command without the
+// trailing newline.
+func readPassphrase(command string) (string, error) {
+	out, err := readKeyMaterial("", command)
+	if err != nil {
+		return "", err
+	}
+	passphrase := strings.TrimSuffix(strings.TrimSuffix(string(out), "\n"), "\r")
+	if passphrase == "" {
+		return "", errors.New("passphrase command returned an empty passphrase")
+	}
+	return passphrase, nil
+}
+
+// parseRecipients parses the age recipients of the recipient flags and of the
+// recipients file, which contains one recipient per line.
+func parseRecipients(recipients []string, recipientsFile string) (*exportEncryption, error) {
+	lines := recipients
+	if recipientsFile != "" {
+		data, err := os.ReadFile(recipientsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recipients file: %w", err)
+		}
+		lines = append(lines, strings.Split(string(data), "\n")...)
+	}
+
+	enc := &exportEncryption{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		r, err := age.ParseRecipients(strings.NewReader(line))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q recipient: %w", line, err)
+		}
+		enc.Recipients = append(enc.Recipients, r...)
+		enc.Names = append(enc.Names, line)
+	}
+	if len(enc.Recipients) == 0 {
+		return nil, errors.New("no recipients found")
+	}
+	return enc, nil
+}
+
+// getExportEncryption returns the encryption for the recipients or for the
+// passphrase returned by the command.
+func getExportEncryption(recipients []string, recipientsFile, passphraseCmd string) (*exportEncryption, error) {
+	if passphraseCmd == "" {
+		return parseRecipients(recipients, recipientsFile)
+	}
+
+	passphrase, err := readPassphrase(passphraseCmd)
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use passphrase: %w", err)
+	}
+	return &exportEncryption{Recipients: []age.Recipient{r}, Names: []string{passphraseRecipient}}, nil
+}
+
+// loadIdentities loads the age identities of the identity files and of the
+// passphrase returned by the command to decrypt export files.
+func loadIdentities(identityFiles []string, passphraseCmd string) ([]age.Identity, error) {
+	var identities []age.Identity
+	for _, path := range identityFiles {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read identity: %w", err)
+		}
+		ids, err := age.ParseIdentities(f)
+		f.Close() //nolint:errcheck
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s identity: %w", path, err)
+		}
+		identities = append(identities, ids...)
+	}
+
+	if passphraseCmd != "" {
+		passphrase, err := readPassphrase(passphraseCmd)
+		if err != nil {
+			return nil, err
+		}
+		id, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to use passphrase: %w", err)
+		}
+		identities = append(identities, id)
+	}
+
+	return identities, nil
+}
+
+// encryptReader returns a reader of the encrypted r.
+func encryptReader(r io.Reader, enc *exportEncryption) (io.Reader, error) {
+	encrypted, err := age.EncryptReader(r, enc.Recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+	return encrypted, nil
+}
+
+// decryptReader returns a reader of the decrypted r.
+func decryptReader(r io.Reader, identities []age.Identity) (io.Reader, error) {
+	if len(identities) == 0 {
+		return nil, errors.New("the export file is encrypted, use --identity or --passphrase-cmd to decrypt it")
+	}
+	decrypted, err := age.Decrypt(r, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return decrypted, nil
+}
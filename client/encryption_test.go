@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func TestEncryptedExportFile(t *testing.T) {
+	ctx := context.Background()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipientsFile := filepath.Join(t.TempDir(), "recipients.txt")
+	data := "# audit team\n" + identity.Recipient().String() + "\n\n"
+	if err := os.WriteFile(recipientsFile, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	enc, err := getExportEncryption(nil, recipientsFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(enc.Names) != 1 || enc.Names[0] != identity.Recipient().String() {
+		t.Fatalf("unexpected recipients: %v", enc.Names)
+	}
+
+	const contents = `[{"id":"a"}]`
+	destination := fileDestination{Dir: t.TempDir()}
+	encrypted, err := encryptReader(compressReader(strings.NewReader(contents), CompressionGzip), enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := ExportFile{
+		Format:      ExportFormatJSON,
+		Compression: CompressionGzip,
+		FileName:    "export",
+		Contents:    encrypted,
+		Encryption:  enc.Names,
+	}
+	if err := destination.Upload(ctx, f); err != nil {
+		t.Fatal(err)
+	}
+	if f.ObjectName() != "export.json.gz.age" {
+		t.Fatalf("unexpected object name: %s", f.ObjectName())
+	}
+
+	tests := []struct {
+		name       string
+		identities []age.Identity
+		ok         bool
+	}{
+		{"recipient", []age.Identity{identity}, true},
+		{"other identity", []age.Identity{other}, false},
+		{"no identity", nil, false},
+	}
+	for _, tc := range tests {
+		r, err := openExportFile(ctx, destination, f.ObjectName(), tc.identities)
+		if !tc.ok {
+			if err == nil {
+				r.Close() //nolint:errcheck
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		result, err := io.ReadAll(r)
+		r.Close() //nolint:errcheck
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+		}
+		if string(result) != contents {
+			t.Errorf("%s: expected %q, got %q", tc.name, contents, result)
+		}
+	}
+}
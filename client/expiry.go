@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// getExportExpiry returns the expiry time of the export files from the
+// expire-after or expire-at flag. Relative times are evaluated against the
+// creation time of the export, therefore resumed exports expire at the same
+// time as the files, which were uploaded before the interruption. A zero
+// time is returned, when no expiry is requested.
+func getExportExpiry(expireAfter, expireAt string, createdAt time.Time) (time.Time, error) {
+	var deleteAt time.Time
+	switch {
+	case expireAfter != "" && expireAt != "":
+		return time.Time{}, errors.New("expire-after and expire-at flags cannot be both specified")
+	case expireAfter != "":
+		d, err := parseDuration(expireAfter)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse expire-after: %w", err)
+		}
+		deleteAt = createdAt.Add(d)
+	case expireAt != "":
+		var err error
+		deleteAt, err = parseTimeExpr(expireAt, createdAt, timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse expire-at: %w", err)
+		}
+		if !deleteAt.After(createdAt) {
+			return time.Time{}, fmt.Errorf("expire-at %s is not in the future", deleteAt.Format(time.RFC3339))
+		}
+	default:
+		return time.Time{}, nil
+	}
+	return deleteAt.Truncate(time.Second), nil
+}
+
+// expiringDestination sets the expiry time of every uploaded object,
+// including the manifests and the signatures.
+type expiringDestination struct {
+	exportDestination
+	DeleteAt time.Time
+}
+
+func (d expiringDestination) Upload(ctx context.Context, f ExportFile) error {
+	f.DeleteAt = d.DeleteAt
+	return d.exportDestination.Upload(ctx, f)
+}
@@ -0,0 +1,41 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExportExpiry(t *testing.T) {
+	createdAt := time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		ExpireAfter string
+		ExpireAt    string
+		Expected    time.Time
+	}{
+		{"", "", time.Time{}},
+		{"90d", "", createdAt.Add(90 * 24 * time.Hour)},
+		{"1w12h", "", createdAt.Add(7*24*time.Hour + 12*time.Hour)},
+		{"", "2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"", "+30d", createdAt.Add(30 * 24 * time.Hour)},
+	}
+	for _, c := range cases {
+		result, err := getExportExpiry(c.ExpireAfter, c.ExpireAt, createdAt)
+		if err != nil {
+			t.Errorf("failed to parse %q/%q: %s", c.ExpireAfter, c.ExpireAt, err)
+			continue
+		}
+		if !result.Equal(c.Expected) {
+			t.Errorf("expected %q/%q to expire at %s, got %s", c.ExpireAfter, c.ExpireAt, c.Expected, result)
+		}
+	}
+
+	for _, c := range [][2]string{{"-90d", ""}, {"0d", ""}, {"2024-06-01", ""}, {"", "2024-01-01"}, {"", "7d"}, {"90d", "2025-01-01"}} {
+		if _, err := getExportExpiry(c[0], c[1], createdAt); err == nil {
+			t.Errorf("expected %q/%q to fail", c[0], c[1])
+		}
+	}
+}
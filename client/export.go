@@ -5,15 +5,19 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
 	"github.com/spf13/cobra"
@@ -77,25 +81,124 @@ func convertToRequestedFormat(buf *bytes.Buffer, allEvents []events.Event, forma
 var ExportCmd = &cobra.Command{
 	Use:   "export",
 	Args:  cobra.ExactArgs(0),
-	Short: "Export Hermes events to Swift",
-	Long: `Export Hermes events to Swift storage container.
-Exports can be saved in different formats (json, csv, yaml) for further processing or archival.`,
+	Short: "Export Hermes events to Swift, a local directory or stdout",
+	Long: `Export Hermes events to a Swift storage container, a local directory or stdout.
+Exports can be saved in different formats (json, csv, yaml) for further processing or archival.
+The destination is selected by --output:
+
+  swift://container/prefix   upload to a Swift container, optionally under an object name prefix
+  file:///path/to/directory  write into a local directory
+  -                          write to stdout`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// resumed exports use the flags of the interrupted export
+		resume, err := cmd.Flags().GetString("resume")
+		if err != nil {
+			return err
+		}
+		if resume != "" {
+			cp, err := loadExportCheckpoint(resume)
+			if err != nil {
+				return err
+			}
+			if err := cp.RestoreFlags(cmd.Flags()); err != nil {
+				return err
+			}
+		}
+
 		if err := viper.BindPFlags(cmd.Flags()); err != nil {
 			return fmt.Errorf("failed to bind flags: %w", err)
 		}
 
-		// Validate container name is provided
-		if viper.GetString("container") == "" {
-			return errors.New("container name is required")
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+
+		if partitionBy := viper.GetString("partition-by"); partitionBy != "" {
+			if spec.Kind == destinationStdout {
+				return errors.New("partitioned exports cannot be written to stdout")
+			}
+			if _, _, err := getPartitioning(partitionBy, viper.GetString("partition-template")); err != nil {
+				return err
+			}
+		} else if viper.GetString("partition-template") != "" {
+			return errors.New("partition-template flag requires partition-by flag")
+		}
+
+		if err := verifyFilterFlags(); err != nil {
+			return err
+		}
+
+		if filename, nameTemplate := viper.GetString("filename"), viper.GetString("name-template"); filename != "" && nameTemplate != "" {
+			return errors.New("filename and name-template flags cannot be both specified")
+		} else if filename != "" {
+			if err := verifyExportName(filename); err != nil {
+				return err
+			}
+		} else if nameTemplate != "" {
+			if err := verifyNameTemplate(nameTemplate); err != nil {
+				return err
+			}
+		}
+		if _, err := parseCollisionPolicy(viper.GetString("on-collision")); err != nil {
+			return err
 		}
 
 		// Validate format
-		_, err := parseExportFormat(viper.GetString("format"))
+		_, err = parseExportFormat(viper.GetString("format"))
 		if err != nil {
 			return err
 		}
 
+		if _, err := parseCompression(viper.GetString("compress")); err != nil {
+			return err
+		}
+
+		if viper.GetString("sign-key") != "" || viper.GetString("sign-key-cmd") != "" {
+			if spec.Kind == destinationStdout {
+				return errors.New("cannot combine sign-key flags with stdout output")
+			}
+			if viper.GetString("sign-key") != "" && viper.GetString("sign-key-cmd") != "" {
+				return errors.New("sign-key and sign-key-cmd flags cannot be both specified")
+			}
+		}
+
+		if viper.GetString("expire-after") != "" || viper.GetString("expire-at") != "" {
+			if spec.Kind != destinationSwift {
+				return errors.New("expire-after and expire-at flags require a Swift output")
+			}
+			if _, err := getExportExpiry(viper.GetString("expire-after"), viper.GetString("expire-at"), time.Now()); err != nil {
+				return err
+			}
+		}
+
+		if viper.GetBool("encrypt") {
+			recipients, recipientsFile := viper.GetStringSlice("recipient"), viper.GetString("recipients-file")
+			switch {
+			case viper.GetString("passphrase-cmd") != "" && (len(recipients) > 0 || recipientsFile != ""):
+				return errors.New("cannot combine passphrase-cmd flag with recipient flags")
+			case viper.GetString("passphrase-cmd") == "":
+				if _, err := parseRecipients(recipients, recipientsFile); err != nil {
+					return fmt.Errorf("encrypt flag requires recipient, recipients-file or passphrase-cmd flag: %w", err)
+				}
+			}
+		} else if len(viper.GetStringSlice("recipient")) > 0 || viper.GetString("recipients-file") != "" || viper.GetString("passphrase-cmd") != "" {
+			return errors.New("recipient, recipients-file and passphrase-cmd flags require encrypt flag")
+		}
+
+		if viper.GetBool("incremental") && viper.GetString("time") != "" {
+			return errors.New("cannot combine time flag with incremental flag")
+		}
+		if !viper.GetBool("incremental") && viper.GetString("state-file") != "" {
+			return errors.New("state-file flag requires incremental flag")
+		}
+		if !viper.GetBool("incremental") && cmd.Flags().Changed("settle") {
+			return errors.New("settle flag requires incremental flag")
+		}
+		if viper.GetDuration("settle") < 0 {
+			return errors.New("--settle cannot be negative")
+		}
+
 		return verifyGlobalFlags(defaultListKeyOrder)
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -112,90 +215,373 @@ Exports can be saved in different formats (json, csv, yaml) for further processi
 			return fmt.Errorf("failed to create Hermes client: %w", err)
 		}
 
-		fmt.Fprintf(os.Stderr, "Fetching events...\n")
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		destination, err := newExportDestination(ctx, client.ProviderClient, spec)
+		if err != nil {
+			return err
+		}
+		if keyFile, keyCmd := viper.GetString("sign-key"), viper.GetString("sign-key-cmd"); keyFile != "" || keyCmd != "" {
+			data, err := readKeyMaterial(keyFile, keyCmd)
+			if err != nil {
+				return err
+			}
+			key, err := parseSigningKey(data)
+			if err != nil {
+				return err
+			}
+			destination = signingDestination{exportDestination: destination, Key: key}
+		}
 
-		var allEvents []events.Event
-		var bar *pb.ProgressBar
+		var cp *exportCheckpoint
+		if resume := viper.GetString("resume"); resume != "" {
+			cp, err = loadExportCheckpoint(resume)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Resuming export %s\n", cp.FileName)
+		}
 
-		logg.Debug("fetching events matching specified criteria")
+		listOpts, err := buildListOpts(time.Now())
+		if err != nil {
+			return err
+		}
+		applyUserLimit(&listOpts, viper.GetInt("limit"))
+		if cp != nil {
+			// relative time flags must not be evaluated again
+			listOpts = cp.ListOpts
+		}
+
+		// Incremental exports continue from the watermark of the previous
+		// export, the time flags are used by the first export only
+		var stateStore exportStateStore
+		var watermark *eventWatermark
+		if viper.GetBool("incremental") {
+			stateStore, err = getExportStateStore(destination, viper.GetString("state-file"))
+			if err != nil {
+				return err
+			}
+			state, err := stateStore.Load(ctx)
+			if err != nil {
+				return err
+			}
+			if state != nil {
+				watermark = state.Watermark()
+				listOpts.Time = slices.DeleteFunc(listOpts.Time, func(q events.DateQuery) bool {
+					return q.Filter == events.DateFilterGTE
+				})
+				listOpts.Time = append(listOpts.Time, events.DateQuery{
+					Date:   watermark.Time,
+					Filter: events.DateFilterGTE,
+				})
+				fmt.Fprintf(os.Stderr, "Continuing export from %s\n", watermark.Time.Format(time.RFC3339))
+			} else {
+				watermark = &eventWatermark{}
+				fmt.Fprintf(os.Stderr, "No export state found in %s, starting a new incremental export\n", stateStore.Location())
+			}
+			// the watermark must not skip events, when the limit truncates the export
+			listOpts.Sort = "time:asc"
+			// the watermark must not pass events, which are not indexed yet
+			listOpts.Time = capTimeFilter(listOpts.Time, time.Now().Add(-viper.GetDuration("settle")).Truncate(time.Second))
+		}
 
-		listOpts := buildListOpts()
-		if err = getEvents(ctx, client, &allEvents, listOpts, viper.GetInt("limit"), true, &bar); err != nil {
-			if bar != nil {
-				bar.Finish()
+		if cp == nil {
+			filename, err := getExportName(ctx, destination, spec, listOpts)
+			if err != nil {
+				return err
+			}
+
+			cp, err = newExportCheckpoint(viper.GetString("checkpoint"), getChangedFlags(cmd), filename, listOpts)
+			if err != nil {
+				return err
+			}
+			cp.InMemory = viper.GetBool("encrypt")
+			if cp.InMemory {
+				fmt.Fprintf(os.Stderr, "The events of encrypted exports are not stored in the checkpoint, a resumed export fetches them again\n")
 			}
-			return fmt.Errorf("failed to list events: %w", err)
+			if err := cp.Save(); err != nil {
+				return err
+			}
+		}
+
+		deleteAt, err := getExportExpiry(viper.GetString("expire-after"), viper.GetString("expire-at"), cp.CreatedAt)
+		if err != nil {
+			return err
 		}
-		if bar != nil {
-			bar.Finish()
+		if !deleteAt.IsZero() {
+			destination = expiringDestination{exportDestination: destination, DeleteAt: deleteAt}
+			fmt.Fprintf(os.Stderr, "The exported files expire at %s\n", deleteAt.Format(time.RFC3339))
 		}
 
+		err = runExport(ctx, client, destination, spec, cp, stateStore, watermark)
+		if errors.Is(err, errNoEvents) {
+			return errors.Join(err, cp.Remove())
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\nThe export can be resumed using: hermescli export --resume %s\n", cp.Path)
+			return err
+		}
+
+		return cp.Remove()
+	},
+}
+
+// getExportName returns the name of a new export from the filename or the
+// name-template flag. Unless the export is written to stdout, the collision
+// policy is applied to names of existing exports.
+func getExportName(ctx context.Context, destination exportDestination, spec destinationSpec, listOpts events.ListOpts) (string, error) {
+	filename := viper.GetString("filename")
+	if filename == "" {
+		template := viper.GetString("name-template")
+		if template == "" {
+			template = defaultNameTemplate
+		}
+		values, err := nameTemplateValues(listOpts, ExportFormat(viper.GetString("format")), time.Now())
+		if err != nil {
+			return "", err
+		}
+		filename = expandPartitionTemplate(template, values)
+	}
+	if err := verifyExportName(filename); err != nil {
+		return "", err
+	}
+
+	if spec.Kind == destinationStdout {
+		return filename, nil
+	}
+	policy, err := parseCollisionPolicy(viper.GetString("on-collision"))
+	if err != nil {
+		return "", err
+	}
+	return resolveExportName(ctx, destination, filename, policy)
+}
+
+// errNoEvents is returned, when no events match the export filters
+var errNoEvents = errors.New("no events found matching the specified criteria")
+
+// runExport fetches the events and writes them to the destination. The
+// progress is recorded in the checkpoint.
+func runExport(ctx context.Context, client *gophercloud.ServiceClient, destination exportDestination, spec destinationSpec, cp *exportCheckpoint, stateStore exportStateStore, watermark *eventWatermark) error {
+	fmt.Fprintf(os.Stderr, "Fetching events...\n")
+
+	logg.Debug("fetching events matching specified criteria")
+
+	allEvents, err := cp.FetchEvents(ctx, client, viper.GetInt("limit"), viper.GetInt("concurrency"))
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	if watermark != nil {
+		allEvents = watermark.Filter(allEvents)
 		if len(allEvents) == 0 {
-			return errors.New("no events found matching the specified criteria")
+			fmt.Fprintf(os.Stderr, "No new events since %s\n", watermark.Time.Format(time.RFC3339))
+			return nil
 		}
+	}
+
+	if len(allEvents) == 0 {
+		return errNoEvents
+	}
+
+	fmt.Fprintf(os.Stderr, "\nFound %d events to export\n", len(allEvents))
 
-		fmt.Fprintf(os.Stderr, "\nFound %d events to export\n", len(allEvents))
+	filename := cp.FileName
+	format, err := parseExportFormat(viper.GetString("format"))
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+	compression, err := parseCompression(viper.GetString("compress"))
+	if err != nil {
+		return err
+	}
+	var encryption *exportEncryption
+	if viper.GetBool("encrypt") {
+		encryption, err = getExportEncryption(viper.GetStringSlice("recipient"), viper.GetString("recipients-file"), viper.GetString("passphrase-cmd"))
+		if err != nil {
+			return err
+		}
+	}
+	segmentSize := uint64(viper.GetInt("segment-size")) * 1024 * 1024 // Convert MB to bytes
+
+	// Split events into partitions, which are exported into separate
+	// files below the filename
+	partitions := []exportPartition{{Path: filename, Events: allEvents}}
+	var partitionKeys []PartitionKey
+	var template string
+	if partitionBy := viper.GetString("partition-by"); partitionBy != "" {
+		partitionKeys, template, err = getPartitioning(partitionBy, viper.GetString("partition-template"))
+		if err != nil {
+			return err
+		}
+		partitions, err = partitionEvents(allEvents, partitionKeys, template)
+		if err != nil {
+			return err
+		}
+		for i := range partitions {
+			partitions[i].Path = filename + "/" + partitions[i].Path
+		}
+	}
 
-		// Convert events to desired format
-		fmt.Fprintf(os.Stderr, "Converting to %s format...\n", viper.GetString("format"))
-		var buf bytes.Buffer
-		if err = convertToRequestedFormat(&buf, allEvents, viper.GetString("format")); err != nil {
+	// Convert events to desired format
+	fmt.Fprintf(os.Stderr, "Converting to %s format...\n", format)
+	bufs := make([]bytes.Buffer, len(partitions))
+	var totalSize int
+	for i, p := range partitions {
+		if err = convertToRequestedFormat(&bufs[i], p.Events, string(format)); err != nil {
 			return fmt.Errorf("failed to convert events: %w", err)
 		}
+		totalSize += bufs[i].Len()
+	}
 
-		dataSize := float64(buf.Len()) / 1024 / 1024 // Convert to MB
-		fmt.Fprintf(os.Stderr, "Uploading %.1fMB to Swift...\n", dataSize)
+	dataSize := float64(totalSize) / 1024 / 1024 // Convert to MB
+	if len(partitions) > 1 {
+		fmt.Fprintf(os.Stderr, "Writing %.1fMB in %d files to %s...\n", dataSize, len(partitions), spec.Kind)
+	} else {
+		fmt.Fprintf(os.Stderr, "Writing %.1fMB to %s...\n", dataSize, spec.Kind)
+	}
 
-		// Create upload progress bar
-		uploadBar := pb.Full.Start64(int64(buf.Len()))
-		uploadBar.Set(pb.Bytes, true)
-		uploadBar.Set(pb.Terminal, true) // Enable terminal features
-		uploadBar.SetWidth(0)
-		defer uploadBar.Finish()
+	// Create upload progress bar
+	uploadBar := pb.Full.Start64(int64(totalSize))
+	uploadBar.Set(pb.Bytes, true)
+	uploadBar.Set(pb.Terminal, true) // Enable terminal features
+	uploadBar.SetWidth(0)
+	defer uploadBar.Finish()
+
+	// Compress and encrypt while uploading, the progress bar counts the raw
+	// bytes and shows the compressed bytes as a suffix. The checksums of the
+	// stored and of the plain data are computed for the integrity manifest.
+	compressedReader := &compressedCounter{Bar: uploadBar}
+	upload := func(f ExportFile) (stored, content *hashingReader, err error) {
+		content = newHashingReader(f.Contents)
+		var r io.Reader = content
+		if compression != CompressionNone {
+			compressed := compressReader(content, compression)
+			defer compressed.Close() //nolint:errcheck
+			compressedReader.Reader = compressed
+			r = compressedReader
+		}
+		if encryption != nil {
+			r, err = encryptReader(r, encryption)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		stored = content
+		if compression != CompressionNone || encryption != nil {
+			stored = newHashingReader(r)
+			r = stored
+		}
+		f.Contents = r
+		return stored, content, destination.Upload(ctx, f)
+	}
+
+	manifest := exportManifest{
+		CreatedAt:   time.Now().UTC(),
+		PartitionBy: partitionKeys,
+		Template:    template,
+		Format:      format,
+		Compression: compression,
+		Events:      len(allEvents),
+	}
+	var exportFile ExportFile
+	for i, p := range partitions {
+		exportFile = ExportFile{
+			Format:      format,
+			Compression: compression,
+			FileName:    p.Path,
+			SegmentSize: segmentSize,
+		}
+		if encryption != nil {
+			exportFile.Encryption = encryption.Names
+		}
+		manifestPartition := newExportManifestPartition(strings.TrimPrefix(exportFile.ObjectName(), filename+"/"), p.Events)
+
+		// skip files, which were uploaded before the interruption
+		progress := cp.File(exportFile.ObjectName())
+		if progress.Done {
+			uploadBar.Add(bufs[i].Len())
+			manifest.Partitions = append(manifest.Partitions, manifestPartition)
+			continue
+		}
 
 		// Wrap the buffer in a progress reader
-		progressReader := &progressReader{
-			Reader: &buf,
+		current := uploadBar.Current()
+		exportFile.Contents = &progressReader{
+			Reader: bytes.NewReader(bufs[i].Bytes()),
 			Bar:    uploadBar,
 		}
+		exportFile.Segments = progress.Segments
+		exportFile.OnSegment = func(s UploadedSegment) error {
+			progress.Segments = append(progress.Segments, s)
+			return cp.Save()
+		}
 
-		// Initialize Swift container
-		container, err := InitializeSwiftContainer(
-			ctx,
-			client.ProviderClient,
-			viper.GetString("container"),
-		)
+		stored, content, err := upload(exportFile)
+		if errors.Is(err, errSegmentMismatch) {
+			// the contents differ from the interrupted upload, e.g. due to
+			// a different compression, therefore all segments are uploaded
+			// again and the previous ones are left for cleanup
+			log.Printf("[WARNING] Uploading %s again: %s", exportFile.ObjectName(), err)
+			progress.Segments = nil
+			uploadBar.SetCurrent(current)
+			exportFile.Segments = nil
+			exportFile.Contents = &progressReader{
+				Reader: bytes.NewReader(bufs[i].Bytes()),
+				Bar:    uploadBar,
+			}
+			stored, content, err = upload(exportFile)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to initialize Swift container: %w", err)
+			return err
 		}
 
-		// Use hyphenated timestamp format for safe and sortable filenames
-		const timeFormat = "2006-01-02-150405"
-		// Create and configure export file
-		filename := viper.GetString("filename")
-		if filename == "" {
-			filename = "hermes-export-" + time.Now().Format(timeFormat)
+		if spec.Kind == destinationStdout {
+			fmt.Fprintf(os.Stderr, "SHA-256 of %s: %s\n", exportFile.ObjectName(), stored.Sum())
+		} else {
+			m := newIntegrityManifest(exportFile, stored, content, p.Events, cp.ListOpts, cp.Total)
+			if err := uploadIntegrityManifest(ctx, destination, m, segmentSize); err != nil {
+				return err
+			}
 		}
 
-		format, err := parseExportFormat(viper.GetString("format"))
-		if err != nil {
-			return fmt.Errorf("invalid format: %w", err)
+		progress.Done = true
+		if err := cp.Save(); err != nil {
+			return err
 		}
-		exportFile := ExportFile{
-			Format:      format,
-			FileName:    filename,
-			SegmentSize: uint64(viper.GetInt("segment-size")) * 1024 * 1024, // Convert MB to bytes
-			Contents:    progressReader,
+
+		manifest.Partitions = append(manifest.Partitions, manifestPartition)
+		// release the uploaded data
+		bufs[i] = bytes.Buffer{}
+	}
+	uploadBar.Finish()
+
+	location := destination.Location(exportFile)
+	if partitionKeys != nil {
+		manifestFile, err := uploadExportManifest(ctx, destination, manifest, filename, segmentSize)
+		if err != nil {
+			return err
 		}
+		location = destination.Location(manifestFile)
+	}
 
-		// Upload to Swift
-		if err := exportFile.UploadTo(ctx, container); err != nil {
-			return fmt.Errorf("failed to upload to Swift: %w", err)
+	// the watermark is advanced only after a successful upload
+	if stateStore != nil {
+		if err := stateStore.Save(ctx, newExportState(watermark, location)); err != nil {
+			return fmt.Errorf("events were exported to %s, but the next incremental export will export them again: %w", location, err)
 		}
+	}
 
-		fmt.Fprintf(os.Stderr, "\nSuccessfully exported %d events\n", len(allEvents))
-		return nil
-	},
+	if compression != CompressionNone {
+		fmt.Fprintf(os.Stderr, "Compressed %.1fMB to %.1fMB using %s\n", dataSize, float64(compressedReader.Total)/1024/1024, compression)
+	}
+	if partitionKeys != nil {
+		fmt.Fprintf(os.Stderr, "\nSuccessfully exported %d events into %d partitions listed in %s\n", len(allEvents), len(partitions), location)
+	} else {
+		fmt.Fprintf(os.Stderr, "\nSuccessfully exported %d events to %s\n", len(allEvents), location)
+	}
+	return nil
 }
 
 // progressReader wraps an io.Reader to update a progress bar
@@ -212,88 +598,62 @@ func (pr *progressReader) Read(p []byte) (n int, err error) {
 	return
 }
 
+// compressedCounter wraps a compressed io.Reader to show the amount of
+// compressed bytes in the progress bar
+type compressedCounter struct {
+	Reader io.Reader
+	Bar    *pb.ProgressBar
+	Total  int64
+}
+
+func (cc *compressedCounter) Read(p []byte) (n int, err error) {
+	n, err = cc.Reader.Read(p)
+	if n > 0 {
+		cc.Total += int64(n)
+		cc.Bar.Set("suffix", fmt.Sprintf("(%.1fMB compressed)", float64(cc.Total)/1024/1024))
+	}
+	return
+}
+
 func init() {
 	initExportCmdFlags()
 	RootCmd.AddCommand(ExportCmd)
 }
 
 func initExportCmdFlags() {
-	ExportCmd.Flags().String("container", "", "Swift container name (required)")
+	ExportCmd.Flags().StringP("output", "o", "", "export destination: swift://container/prefix, file:///path/to/directory or - for stdout")
+	ExportCmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
 	ExportCmd.Flags().String("format", "json", "Output format (json|csv|yaml)")
+	ExportCmd.Flags().String("compress", "", "compress the export file (gzip|zstd)")
+	ExportCmd.Flags().Bool("encrypt", false, "encrypt the export file using age")
+	ExportCmd.Flags().StringSlice("recipient", nil, "age recipient to encrypt the export file for, can be specified multiple times")
+	ExportCmd.Flags().String("recipients-file", "", "file with one age recipient per line to encrypt the export file for")
+	ExportCmd.Flags().String("passphrase-cmd", "", "command, which prints the passphrase to encrypt the export file with")
+	ExportCmd.Flags().Bool("incremental", false, "continue from the last event of the previous incremental export")
+	ExportCmd.Flags().Duration("settle", cacheSettleTime, "incremental exports stop at events older than the duration, because Hermes may index newer events late")
+	ExportCmd.Flags().String("state-file", "", "local file of the incremental export state (default: "+defaultExportStateName+" next to the exported files)")
+	ExportCmd.Flags().String("partition-by", "", "split the export into files by a comma separated list of partitions (day|hour|project|source)")
+	ExportCmd.Flags().String("partition-template", "", "path template of the partition files below the filename, e.g. {project}/{yyyy}/{mm}/{dd} (default: the partitions in the given order)")
+	ExportCmd.Flags().String("sign-key", "", "ed25519 private key file to write a detached signature next to every exported object")
+	ExportCmd.Flags().String("sign-key-cmd", "", "command, which prints the ed25519 private key to sign the exported objects")
+	ExportCmd.Flags().String("expire-after", "", "delete the exported objects from Swift after this duration, e.g. 90d")
+	ExportCmd.Flags().String("expire-at", "", "delete the exported objects from Swift at this time, e.g. 2025-01-01")
+	ExportCmd.Flags().String("checkpoint", "", "checkpoint file to resume an interrupted export (default: in the user cache directory)")
+	ExportCmd.Flags().String("resume", "", "resume the interrupted export of the checkpoint file")
 	ExportCmd.Flags().String("filename", "", "Name of the output file (default: hermes-export-{timestamp})")
+	ExportCmd.Flags().String("name-template", "", "name template of the output file with the {project}, {region}, {start}, {end}, {now}, {format} and {filters_hash} placeholders (default: "+defaultNameTemplate+")")
+	ExportCmd.Flags().String("on-collision", string(CollisionFail), "what to do, when an export with the same name exists (fail|overwrite|suffix)")
 
 	// Use same default as list command
 	ExportCmd.Flags().UintP("limit", "l", maxOffset, "limit number of events to export (default: 10000)")
+	ExportCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
 
 	// Hidden advanced options
 	ExportCmd.Flags().Int("segment-size", 100, "Size of segments in MB for large file uploads")
 	ExportCmd.Flags().MarkHidden("segment-size") //nolint:errcheck
 
 	// Add all list command flags for filtering
-	ExportCmd.Flags().StringP("target-type", "", "", "filter events by a target type")
-	ExportCmd.Flags().StringP("target-id", "", "", "filter events by a target ID")
-	ExportCmd.Flags().StringP("initiator-id", "", "", "filter events by an initiator ID")
-	ExportCmd.Flags().StringP("initiator-name", "", "", "filter events by an initiator name")
-	ExportCmd.Flags().StringP("action", "", "", "filter events by an action")
-	ExportCmd.Flags().StringP("outcome", "", "", "filter events by an outcome")
-	ExportCmd.Flags().StringP("time", "", "", "filter events by time")
-	ExportCmd.Flags().StringP("time-start", "", "", "filter events from time")
-	ExportCmd.Flags().StringP("time-end", "", "", "filter events till time")
-	ExportCmd.Flags().StringP("project-id", "", "", "filter events by the project or domain ID (admin only)")
-	ExportCmd.Flags().BoolP("all-projects", "A", false, "include all projects and domains (admin only)")
-}
-
-// buildListOpts creates ListOpts from viper flags
-func buildListOpts() events.ListOpts {
-	projectID := viper.GetString("project-id")
-	if viper.GetBool("all-projects") {
-		projectID = "*"
-	}
-
-	listOpts := events.ListOpts{
-		Limit:         viper.GetInt("limit"),
-		TargetType:    viper.GetString("target-type"),
-		TargetID:      viper.GetString("target-id"),
-		InitiatorID:   viper.GetString("initiator-id"),
-		InitiatorName: viper.GetString("initiator-name"),
-		Action:        viper.GetString("action"),
-		Outcome:       viper.GetString("outcome"),
-		ProjectID:     projectID,
-	}
-
-	// Handle time flags
-	if t := viper.GetString("time"); t != "" {
-		rt, err := parseTime(t)
-		if err != nil {
-			logg.Error("failed to parse time: %v", err)
-			return listOpts
-		}
-		listOpts.Time = []events.DateQuery{{Date: rt}}
-	}
-
-	if t := viper.GetString("time-start"); t != "" {
-		rt, err := parseTime(t)
-		if err != nil {
-			logg.Error("failed to parse time-start: %v", err)
-			return listOpts
-		}
-		listOpts.Time = append(listOpts.Time, events.DateQuery{
-			Date:   rt,
-			Filter: events.DateFilterGTE,
-		})
-	}
-
-	if t := viper.GetString("time-end"); t != "" {
-		rt, err := parseTime(t)
-		if err != nil {
-			logg.Error("failed to parse time-end: %v", err)
-			return listOpts
-		}
-		listOpts.Time = append(listOpts.Time, events.DateQuery{
-			Date:   rt,
-			Filter: events.DateFilterLTE,
-		})
-	}
-
-	return listOpts
+	addFilterFlags(ExportCmd.Flags())
+	addTimeFilterFlags(ExportCmd.Flags())
+	addSortFlag(ExportCmd.Flags())
 }
@@ -0,0 +1,346 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// csvRowToEvent restores the event attributes of a CSV export row. The CSV
+// format contains the rendered columns only, therefore other attributes stay
+// empty.
+func csvRowToEvent(header, row []string) events.Event {
+	var evt events.Event
+	for i, k := range header {
+		if i >= len(row) {
+			break
+		}
+		v := row[i]
+		switch k {
+		case "ID":
+			evt.ID = v
+		case "Type":
+			evt.EventType = v
+		case "Time":
+			evt.EventTime = v
+		case "Observer":
+			evt.Observer.Name = v
+		case "TypeURI", "Source":
+			evt.Observer.TypeURI = v
+		case "Action":
+			evt.Action = cadf.Action(v)
+		case "Outcome":
+			evt.Outcome = cadf.Outcome(v)
+		case "Target":
+			// the target is rendered as "<type URI> <ID>"
+			evt.Target.TypeURI, evt.Target.ID, _ = strings.Cut(v, " ")
+		case "Initiator":
+			evt.Initiator.Name = v
+		case "InitiatorDomain":
+			evt.Initiator.Domain = v
+		case "InitiatorAddress", "InitiatorAgent":
+			if v == "" {
+				continue
+			}
+			if evt.Initiator.Host == nil {
+				evt.Initiator.Host = &cadf.Host{}
+			}
+			if k == "InitiatorAddress" {
+				evt.Initiator.Host.Address = v
+			} else {
+				evt.Initiator.Host.Agent = v
+			}
+		case "InitiatorAppCredential":
+			evt.Initiator.AppCredentialID = v
+		case "RequestPath":
+			evt.RequestPath = v
+		}
+	}
+	return evt
+}
+
+// readExportedEvents reads the events of the plain contents of an export
+// file.
+func readExportedEvents(r io.Reader, format ExportFormat) ([]events.Event, error) {
+	var allEvents []events.Event
+	switch format {
+	case ExportFormatJSON:
+		if err := json.NewDecoder(r).Decode(&allEvents); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		}
+	case ExportFormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&allEvents); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	case ExportFormatCSV:
+		rows, err := csv.NewReader(r).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		allEvents = make([]events.Event, 0, len(rows)-1)
+		for _, row := range rows[1:] {
+			allEvents = append(allEvents, csvRowToEvent(rows[0], row))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+	return allEvents, nil
+}
+
+// isExportFile returns true for exported event files. Manifests, signatures,
+// segments and the incremental export state are skipped.
+func isExportFile(name string) bool {
+	if _, ok := parseExportFileType(name); !ok {
+		return false
+	}
+	switch {
+	case strings.Contains(name, segmentsSuffix),
+		strings.HasSuffix(name, integrityManifestSuffix+"."+string(ExportFormatJSON)),
+		path.Base(name) == "manifest."+string(ExportFormatJSON),
+		path.Base(name) == defaultExportStateName:
+		return false
+	}
+	return true
+}
+
+// exportListEntry describes an export file in the exports list output
+type exportListEntry struct {
+	Name           string       `json:"name"`
+	Format         ExportFormat `json:"format"`
+	Compression    Compression  `json:"compression,omitempty"`
+	Encrypted      bool         `json:"encrypted"`
+	SizeBytes      uint64       `json:"size_bytes"`
+	LastModified   time.Time    `json:"last_modified"`
+	Events         *int         `json:"events,omitempty"`
+	FirstEventTime *time.Time   `json:"first_event_time,omitempty"`
+	LastEventTime  *time.Time   `json:"last_event_time,omitempty"`
+}
+
+// listExportFiles returns the export files of the destination. The event
+// counts and time ranges are taken from the integrity manifests, when they
+// exist.
+func listExportFiles(ctx context.Context, destination exportDestination, concurrency int) ([]exportListEntry, error) {
+	objects, err := destination.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []exportListEntry
+	for _, o := range objects {
+		fileType, ok := parseExportFileType(o.Name)
+		if !ok || !isExportFile(o.Name) {
+			continue
+		}
+		result = append(result, exportListEntry{
+			Name:         o.Name,
+			Format:       fileType.Format,
+			Compression:  fileType.Compression,
+			Encrypted:    fileType.Encrypted,
+			SizeBytes:    o.SizeBytes,
+			LastModified: o.LastModified,
+		})
+	}
+
+	err = runParallel(ctx, len(result), concurrency, func(ctx context.Context, i int) error {
+		m, _, err := loadIntegrityManifest(ctx, destination, result[i].Name)
+		if isNotFound(err) {
+			// exported before integrity manifests were introduced
+			return nil
+		}
+		if err != nil {
+			log.Printf("[WARNING] Failed to load the integrity manifest of %s: %s", result[i].Name, err)
+			return nil
+		}
+		result[i].Events = &m.Events
+		result[i].FirstEventTime = &m.FirstEventTime
+		result[i].LastEventTime = &m.LastEventTime
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ExportsCmd represents the exports command
+var ExportsCmd = &cobra.Command{
+	Use:   "exports",
+	Args:  cobra.NoArgs,
+	Short: "Manage exported events",
+	Long: `List, read back and prune the files written by the export command to Swift or to a local
+directory.`,
+}
+
+// ExportsListCmd represents the exports list command
+var ExportsListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the exported files",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		if _, err := getExportDestination(viper.GetString("output"), viper.GetString("container")); err != nil {
+			return err
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		destination, err := openExportLocation(ctx, viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		entries, err := listExportFiles(ctx, destination, viper.GetInt("concurrency"))
+		if err != nil {
+			return err
+		}
+
+		switch viper.GetString("format") {
+		case "json":
+			jsonEntries, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", jsonEntries)
+		case "yaml":
+			yamlEntries, err := yaml.Marshal(entries)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s", yamlEntries)
+		default:
+			formatTime := func(t time.Time) string {
+				if timezone != nil {
+					t = t.In(timezone)
+				}
+				return t.Format(time.RFC3339)
+			}
+
+			table := tablewriter.NewTable(os.Stdout,
+				tablewriter.WithRowAlignment(tw.AlignRight),
+			)
+			table.Header("Name", "Format", "Compression", "Encrypted", "Size", "Last Modified", "Events", "First Event", "Last Event")
+			rows := make([][]string, 0, len(entries))
+			for _, e := range entries {
+				row := []string{
+					e.Name,
+					string(e.Format),
+					string(e.Compression),
+					strconv.FormatBool(e.Encrypted),
+					fmt.Sprintf("%.1fMB", float64(e.SizeBytes)/1024/1024),
+					formatTime(e.LastModified),
+					"", "", "",
+				}
+				if e.Events != nil {
+					row[6] = strconv.Itoa(*e.Events)
+					if !e.FirstEventTime.IsZero() {
+						row[7] = formatTime(*e.FirstEventTime)
+						row[8] = formatTime(*e.LastEventTime)
+					}
+				}
+				rows = append(rows, row)
+			}
+			if err := table.Bulk(rows); err != nil {
+				return fmt.Errorf("error appending rows to table: %w", err)
+			}
+			if err := table.Render(); err != nil {
+				return fmt.Errorf("error rendering table: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
+
+// ExportsGetCmd represents the exports get command
+var ExportsGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Print the events of an exported file",
+	Long: `Download an exported file, decrypt and decompress it and print its events like the list command.
+CSV exports contain only the exported columns, other attributes are empty.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		if _, err := getExportDestination(viper.GetString("output"), viper.GetString("container")); err != nil {
+			return err
+		}
+		if _, ok := parseExportFileType(args[0]); !ok {
+			return fmt.Errorf("%s is not an export file, supported extensions: %s", args[0], strings.Join(exportFileExtensions(), ", "))
+		}
+		return verifyGlobalFlags(defaultListKeyOrder)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		destination, err := openExportLocation(ctx, viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+
+		fileType, _ := parseExportFileType(args[0])
+		var identities []age.Identity
+		if fileType.Encrypted {
+			identities, err = loadIdentities(viper.GetStringSlice("identity"), viper.GetString("passphrase-cmd"))
+			if err != nil {
+				return err
+			}
+		}
+
+		r, err := openExportFile(ctx, destination, args[0], identities)
+		if err != nil {
+			return err
+		}
+		defer r.Close() //nolint:errcheck
+
+		allEvents, err := readExportedEvents(r, fileType.Format)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		keyOrder := viper.GetStringSlice("column")
+		if len(keyOrder) == 0 {
+			keyOrder = defaultListKeyOrder
+		}
+		return printEvent(allEvents, viper.GetString("format"), keyOrder)
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{ExportsListCmd, ExportsGetCmd} {
+		cmd.Flags().StringP("output", "o", "", "location of the exports: swift://container/prefix or file:///path/to/directory")
+		cmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
+	}
+	ExportsListCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests to load the integrity manifests")
+	ExportsGetCmd.Flags().StringSlice("identity", nil, "age identity file to decrypt encrypted exports, can be specified multiple times")
+	ExportsGetCmd.Flags().String("passphrase-cmd", "", "command, which prints the passphrase to decrypt encrypted exports")
+	ExportsCmd.AddCommand(ExportsListCmd)
+	ExportsCmd.AddCommand(ExportsGetCmd)
+	RootCmd.AddCommand(ExportsCmd)
+}
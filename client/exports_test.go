@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestReadExportedEvents(t *testing.T) {
+	allEvents := []events.Event{
+		{
+			ID:        "7189ce80-6e73-5ad9-bdc5-dcc47f176378",
+			EventTime: "2024-01-15T09:30:00.000+0000",
+			Action:    cadf.Action("create/role_assignment"),
+			Outcome:   cadf.Outcome("success"),
+			Observer:  cadf.Resource{TypeURI: "service/security"},
+			Target:    cadf.Resource{TypeURI: "data/security/project", ID: "a759dcc2a2384a76b0386bb985952373"},
+			Initiator: cadf.Resource{Name: "admin"},
+		},
+	}
+
+	for _, format := range allExportFormats {
+		var buf bytes.Buffer
+		if err := convertToRequestedFormat(&buf, allEvents, string(format)); err != nil {
+			t.Fatal(err)
+		}
+		result, err := readExportedEvents(&buf, format)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", format, err)
+			continue
+		}
+		if len(result) != 1 {
+			t.Errorf("%s: expected 1 event, got %d", format, len(result))
+			continue
+		}
+		e, r := allEvents[0], result[0]
+		if r.ID != e.ID || r.Action != e.Action || r.Outcome != e.Outcome ||
+			r.Observer.TypeURI != e.Observer.TypeURI || r.Target.TypeURI != e.Target.TypeURI ||
+			r.Target.ID != e.Target.ID || r.Initiator.Name != e.Initiator.Name {
+			t.Errorf("%s: expected %+v, got %+v", format, e, r)
+		}
+	}
+}
+
+func TestListExportFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"2024/hermes-export.json.gz",
+		"2024/hermes-export.json.gz.manifest.json",
+		"2024/hermes-export.json.gz.sig",
+		"hermes-export.csv.age",
+		"hermes-export.yaml-segments/000001",
+		"manifest.json",
+		"notes.txt",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := listExportFiles(context.Background(), fileDestination{Dir: dir}, defaultConcurrency)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	slices.Sort(names)
+	expected := []string{"2024/hermes-export.json.gz", "hermes-export.csv.age"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
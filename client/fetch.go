@@ -0,0 +1,614 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+// defaultConcurrency is the default amount of parallel requests to Hermes
+const defaultConcurrency = 4
+
+// timeWindow is a half-open time range, which contains the events from Start
+// until before End. Time filters are sent to Hermes with a precision of one
+// second, therefore both boundaries are whole seconds. Event times have
+// milliseconds, adjacent windows share their boundary to cover them.
+type timeWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// split bisects the window into two adjacent non-overlapping windows. It
+// returns false, when the window covers a single second only.
+func (w timeWindow) split() (left, right timeWindow, ok bool) {
+	span := w.End.Sub(w.Start)
+	if span < 2*time.Second {
+		return w, w, false
+	}
+	mid := w.Start.Add(span / 2).Truncate(time.Second)
+	return timeWindow{Start: w.Start, End: mid}, timeWindow{Start: mid, End: w.End}, true
+}
+
+// Contains returns true, when the time is within the window.
+func (w timeWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// applyTo returns a copy of the list options restricted to the window.
+func (w timeWindow) applyTo(listOpts events.ListOpts) events.ListOpts {
+	listOpts.Offset = 0
+	listOpts.Time = []events.DateQuery{
+		{Date: w.Start, Filter: events.DateFilterGTE},
+		{Date: w.End, Filter: events.DateFilterLT},
+	}
+	return listOpts
+}
+
+// countEvents returns the amount of events matching the list options without
+// downloading them.
+func countEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts) (int, error) {
+	listOpts.Limit = 1
+	listOpts.Offset = 0
+
+	var total int
+	err := events.List(client, listOpts).EachPage(ctx, func(ctx context.Context, page pagination.Page) (bool, error) {
+		v, err := page.(events.EventPage).Total()
+		if err != nil {
+			return false, fmt.Errorf("failed to extract total: %w", err)
+		}
+		total = v
+		return false, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+
+	return total, nil
+}
+
+// getBoundaryTime returns the time of the first event matching the list
+// options using the given time sort direction, e.g. "time:asc" returns the
+// time of the oldest event.
+func getBoundaryTime(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, sort string) (time.Time, error) {
+	listOpts.Limit = 1
+	listOpts.Offset = 0
+	listOpts.Sort = sort
+
+	var evt *events.Event
+	err := events.List(client, listOpts).EachPage(ctx, func(ctx context.Context, page pagination.Page) (bool, error) {
+		pageEvents, err := events.ExtractEvents(page)
+		if err != nil {
+			return false, fmt.Errorf("failed to extract events: %w", err)
+		}
+		if len(pageEvents) > 0 {
+			evt = &pageEvents[0]
+		}
+		return false, nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to detect the time boundary: %w", err)
+	}
+	if evt == nil {
+		return time.Time{}, errors.New("failed to detect the time boundary: no events found")
+	}
+
+	t, err := parseTime(evt.EventTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse time of the %s event: %w", evt.ID, err)
+	}
+
+	return t, nil
+}
+
+// parseTimeWindow converts the time filters into a time window. Filters are
+// applied with a precision of one second, e.g. "lte" includes the whole
+// second. It returns false, when the time filter cannot be converted, e.g.
+// when an exact time is requested.
+func parseTimeWindow(filter []events.DateQuery) (w timeWindow, hasStart, hasEnd, ok bool) {
+	for _, v := range filter {
+		d := v.Date.Truncate(time.Second)
+		switch v.Filter {
+		case events.DateFilterGTE:
+			w.Start, hasStart = d, true
+		case events.DateFilterGT:
+			w.Start, hasStart = d.Add(time.Second), true
+		case events.DateFilterLTE:
+			w.End, hasEnd = d.Add(time.Second), true
+		case events.DateFilterLT:
+			w.End, hasEnd = d, true
+		default:
+			return w, false, false, false
+		}
+	}
+	return w, hasStart, hasEnd, true
+}
+
+// getTimeWindow converts the time filters of the list options into a time
+// window. Missing boundaries are detected using the oldest and the newest
+// matching event. It returns false, when the time filter cannot be split into
+// windows, e.g. when an exact time is requested.
+func getTimeWindow(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts) (timeWindow, bool, error) {
+	w, hasStart, hasEnd, ok := parseTimeWindow(listOpts.Time)
+	if !ok {
+		return w, false, nil
+	}
+
+	// the window starts at the second of the oldest event and ends after the
+	// second of the newest event, which keeps both events inside
+	if !hasStart {
+		t, err := getBoundaryTime(ctx, client, listOpts, "time:asc")
+		if err != nil {
+			return w, false, err
+		}
+		w.Start = t.Truncate(time.Second)
+	}
+	if !hasEnd {
+		t, err := getBoundaryTime(ctx, client, listOpts, "time:desc")
+		if err != nil {
+			return w, false, err
+		}
+		w.End = t.Truncate(time.Second).Add(time.Second)
+	}
+
+	return w, w.Start.Before(w.End), nil
+}
+
+// planWindows bisects the time window until every resulting window contains
+// less than 10000 events, which is the maximum offset supported by Hermes.
+// The returned windows are sorted in ascending time order.
+func planWindows(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, root timeWindow, concurrency int) ([]timeWindow, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		windows  []timeWindow
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	var plan func(w timeWindow)
+	plan = func(w timeWindow) {
+		defer wg.Done()
+
+		sem <- struct{}{}
+		total, err := countEvents(ctx, client, w.applyTo(listOpts))
+		<-sem
+		if err != nil {
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+			mu.Unlock()
+			return
+		}
+
+		if total == 0 {
+			return
+		}
+
+		if total > maxOffset {
+			if left, right, ok := w.split(); ok {
+				wg.Add(2)
+				go plan(left)
+				go plan(right)
+				return
+			}
+			log.Printf("[WARNING] %d events share the %s time, only the first %d of them will be fetched", total, w.Start.Format(time.RFC3339), maxOffset)
+		}
+
+		mu.Lock()
+		windows = append(windows, w)
+		mu.Unlock()
+	}
+
+	wg.Add(1)
+	go plan(root)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	slices.SortFunc(windows, func(a, b timeWindow) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	return windows, nil
+}
+
+// fetchPages fetches the events matching the list options page by page and
+// passes each page to the handler, until the handler returns false or the
+// Hermes offset limit is reached.
+func fetchPages(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, bar *pb.ProgressBar, handler func([]events.Event) (bool, error)) error {
+	err := events.List(client, listOpts).EachPage(ctx, func(ctx context.Context, page pagination.Page) (bool, error) {
+		pageEvents, err := events.ExtractEvents(page)
+		if err != nil {
+			return false, fmt.Errorf("failed to extract events: %w", err)
+		}
+
+		if bar != nil {
+			bar.Add(len(pageEvents))
+		}
+
+		if ok, err := handler(pageEvents); !ok || err != nil {
+			return false, err
+		}
+
+		nextOffset, err := getNextOffset(page)
+		if err != nil {
+			return false, err
+		}
+
+		// avoid the 500 http code for offsets above the limit
+		return nextOffset < maxOffset, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	return nil
+}
+
+// runParallel calls fn for every index in [0, n) using a bounded amount of
+// parallel calls. It returns the first error and cancels the remaining calls.
+func runParallel(ctx context.Context, n, concurrency int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, max(concurrency, 1))
+	)
+
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			if err := fn(ctx, i); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// windowResult contains the events of a single time window
+type windowResult struct {
+	Index  int
+	Events []events.Event
+	Err    error
+}
+
+// fetchWindows fetches the events of all windows using a bounded amount of
+// parallel requests and passes them to the handler in the order of the
+// windows. Windows, which are fetched ahead of their turn, are kept in memory
+// until all preceding windows are passed to the handler. No further windows
+// are fetched, when the handler returns false.
+func fetchWindows(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, windows []timeWindow, concurrency int, bar *pb.ProgressBar, handler func([]events.Event) (bool, error)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	results := make(chan windowResult)
+
+	go func() {
+		defer close(jobs)
+		for i := range windows {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Go(func() {
+			for i := range jobs {
+				var result []events.Event
+				err := fetchPages(ctx, client, windows[i].applyTo(listOpts), bar, func(page []events.Event) (bool, error) {
+					result = append(result, page...)
+					return true, nil
+				})
+				select {
+				case results <- windowResult{Index: i, Events: result, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// stop wraps up the workers before returning
+	stop := func(err error) error {
+		cancel()
+		for range results {
+			// drain the channel until all workers exit
+		}
+		return err
+	}
+
+	pending := make(map[int][]events.Event)
+	var next int
+	for r := range results {
+		if r.Err != nil {
+			return stop(r.Err)
+		}
+		pending[r.Index] = r.Events
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if ok, err := handler(result); !ok || err != nil {
+				return stop(err)
+			}
+		}
+	}
+
+	if next < len(windows) {
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// eventSink passes events to the handler, skipping events, which were
+// already passed once, until the user limit is reached.
+type eventSink struct {
+	Handler   func([]events.Event) error
+	UserLimit int
+
+	count int
+	seen  map[string]struct{}
+}
+
+// Emit returns false, when the user limit is reached.
+func (s *eventSink) Emit(page []events.Event) (bool, error) {
+	if s.seen == nil {
+		s.seen = make(map[string]struct{})
+	}
+
+	unique := make([]events.Event, 0, len(page))
+	for _, evt := range page {
+		if s.UserLimit > 0 && s.count >= s.UserLimit {
+			break
+		}
+		if _, ok := s.seen[evt.ID]; ok {
+			continue
+		}
+		s.seen[evt.ID] = struct{}{}
+		unique = append(unique, evt)
+		s.count++
+	}
+
+	if len(unique) > 0 {
+		if err := s.Handler(unique); err != nil {
+			return false, err
+		}
+	}
+
+	return s.UserLimit <= 0 || s.count < s.UserLimit, nil
+}
+
+// planFetch counts the events matching the list options and splits the
+// requested time range into time windows with less than 10000 events each,
+// when the requested events are not reachable without the offset limit.
+// Windows follow the requested order, when the events are sorted by time. No
+// windows are returned, when the events are fetched at once.
+func planFetch(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, userLimit, concurrency int) (int, []timeWindow, error) {
+	total, err := countEvents(ctx, client, listOpts)
+	if err != nil {
+		return 0, nil, err
+	}
+	if total <= maxOffset || (userLimit > 0 && userLimit <= maxOffset) {
+		// the requested events are reachable without the offset limit
+		return total, nil, nil
+	}
+
+	root, ok, err := getTimeWindow(ctx, client, listOpts)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		log.Printf("[WARNING] the time filter cannot be split, only the first %d of %d events will be fetched", maxOffset, total)
+		return total, nil, nil
+	}
+
+	windows, err := planWindows(ctx, client, listOpts, root, concurrency)
+	if err != nil {
+		return 0, nil, err
+	}
+	if keys := parseSortKeys(listOpts.Sort); keys[0].Name == "time" && keys[0].Desc {
+		slices.Reverse(windows)
+	}
+
+	return total, windows, nil
+}
+
+// getEvents fetches all events matching the list options and passes them to
+// the handler page by page. When more than 10000 events match, the requested
+// time range is split into time windows with less than 10000 events each,
+// which are fetched in parallel and merged in the requested sort order.
+// Duplicate events are removed by their ID.
+func getEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, userLimit, concurrency int, bar **pb.ProgressBar, handler func([]events.Event) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	total, windows, err := planFetch(ctx, client, listOpts, userLimit, concurrency)
+	if err != nil {
+		return err
+	}
+	if total == 0 {
+		return nil
+	}
+
+	expected := total
+	if userLimit > 0 && userLimit < total {
+		expected = userLimit
+	}
+	if expected > maxOffset {
+		*bar = pb.New(expected)
+		(*bar).SetWriter(os.Stderr)
+		(*bar).Start()
+	}
+
+	sink := &eventSink{Handler: handler, UserLimit: userLimit}
+
+	if len(windows) == 0 {
+		return fetchPages(ctx, client, listOpts, *bar, sink.Emit)
+	}
+
+	keys := parseSortKeys(listOpts.Sort)
+	if keys[0].Name == "time" {
+		// windows follow the requested order, events can be streamed
+		return fetchWindows(ctx, client, listOpts, windows, concurrency, *bar, sink.Emit)
+	}
+
+	// windows are ordered by time, restore the requested order
+	var merged []events.Event
+	err = fetchWindows(ctx, client, listOpts, windows, concurrency, *bar, func(page []events.Event) (bool, error) {
+		merged = append(merged, page...)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	sortEvents(merged, keys)
+
+	_, err = sink.Emit(merged)
+	return err
+}
+
+// sortKey is a single Hermes sort key with its direction
+type sortKey struct {
+	Name string
+	Desc bool
+}
+
+// parseSortKeys parses the Hermes sort parameter. The result always ends
+// with a time sort key, because Hermes sorts by time in descending order by
+// default.
+func parseSortKeys(sort string) []sortKey {
+	var keys []sortKey
+	for v := range strings.SplitSeq(sort, ",") {
+		if v == "" {
+			continue
+		}
+		s := strings.SplitN(v, ":", 2)
+		key := sortKey{Name: s[0]}
+		if len(s) == 2 {
+			key.Desc = s[1] == "desc"
+		}
+		keys = append(keys, key)
+		if key.Name == "time" {
+			return keys
+		}
+	}
+	return append(keys, sortKey{Name: "time", Desc: true})
+}
+
+// eventAttribute returns the value of the Hermes attribute of the event, e.g.
+// of the "action" attribute.
+func eventAttribute(evt events.Event, name string) string {
+	switch name {
+	case "observer_type":
+		return evt.Observer.TypeURI
+	case "target_type":
+		return evt.Target.TypeURI
+	case "target_id":
+		return evt.Target.ID
+	case "initiator_type":
+		return evt.Initiator.TypeURI
+	case "initiator_id":
+		return evt.Initiator.ID
+	case "initiator_name":
+		return evt.Initiator.Name
+	case "outcome":
+		return string(evt.Outcome)
+	case "action":
+		return string(evt.Action)
+	}
+	return ""
+}
+
+// sortEvents sorts the events locally the same way as Hermes does.
+func sortEvents(allEvents []events.Event, keys []sortKey) {
+	times := make(map[string]time.Time)
+	eventTime := func(evt events.Event) time.Time {
+		t, ok := times[evt.EventTime]
+		if !ok {
+			var err error
+			t, err = parseTime(evt.EventTime)
+			if err != nil {
+				// sort events with an invalid time last
+				t = time.Time{}
+			}
+			times[evt.EventTime] = t
+		}
+		return t
+	}
+
+	slices.SortStableFunc(allEvents, func(a, b events.Event) int {
+		for _, key := range keys {
+			var c int
+			if key.Name == "time" {
+				c = eventTime(a).Compare(eventTime(b))
+			} else {
+				c = cmp.Compare(eventAttribute(a, key.Name), eventAttribute(b, key.Name))
+			}
+			if key.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestTimeWindowSplit(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	w := timeWindow{Start: start, End: start.Add(9 * time.Second)}
+
+	left, right, ok := w.split()
+	if !ok {
+		t.Fatal("expected the window to be split")
+	}
+	if !left.Start.Equal(w.Start) || !right.End.Equal(w.End) {
+		t.Errorf("expected the halves to cover %v, got %v and %v", w, left, right)
+	}
+	if !right.Start.Equal(left.End) {
+		t.Errorf("expected adjacent halves sharing their boundary, got %v and %v", left, right)
+	}
+
+	// events between the whole seconds belong to exactly one half
+	for _, offset := range []time.Duration{0, 500 * time.Millisecond, -500 * time.Millisecond} {
+		evtTime := left.End.Add(offset)
+		if left.Contains(evtTime) == right.Contains(evtTime) {
+			t.Errorf("expected the %s event to be in exactly one of %v and %v", evtTime, left, right)
+		}
+	}
+	if !right.Contains(w.End.Add(-time.Millisecond)) || right.Contains(w.End) {
+		t.Errorf("expected %v to end before %s", right, w.End)
+	}
+
+	single := timeWindow{Start: start, End: start.Add(time.Second)}
+	if _, _, ok := single.split(); ok {
+		t.Error("expected a single second window not to be split")
+	}
+}
+
+func TestParseTimeWindow(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	w, hasStart, hasEnd, ok := parseTimeWindow([]events.DateQuery{
+		{Date: start.Add(500 * time.Millisecond), Filter: events.DateFilterGTE},
+		{Date: start.Add(time.Minute), Filter: events.DateFilterLTE},
+	})
+	if !ok || !hasStart || !hasEnd {
+		t.Fatalf("expected a time window, got %v, %v, %v", hasStart, hasEnd, ok)
+	}
+	expected := timeWindow{Start: start, End: start.Add(time.Minute + time.Second)}
+	if !w.Start.Equal(expected.Start) || !w.End.Equal(expected.End) {
+		t.Errorf("expected %v, got %v", expected, w)
+	}
+	if !w.Contains(start.Add(time.Minute + 500*time.Millisecond)) {
+		t.Errorf("expected %v to contain the whole last second", w)
+	}
+
+	if _, _, _, ok := parseTimeWindow([]events.DateQuery{{Date: start}}); ok {
+		t.Error("expected an exact time not to be converted")
+	}
+}
+
+func TestSortEvents(t *testing.T) {
+	allEvents := []events.Event{
+		{ID: "1", EventTime: "2024-05-01T00:00:01+0000", Action: cadf.Action("update")},
+		{ID: "2", EventTime: "2024-05-01T00:00:02+0000", Action: cadf.Action("create")},
+		{ID: "3", EventTime: "2024-05-01T00:00:03+0000", Action: cadf.Action("update")},
+	}
+
+	sortEvents(allEvents, parseSortKeys("action:asc"))
+
+	var ids []string
+	for _, evt := range allEvents {
+		ids = append(ids, evt.ID)
+	}
+	expected := []string{"2", "3", "1"}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected %v order, got %v", expected, ids)
+		}
+	}
+}
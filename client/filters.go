@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// sortKeyNames are the sort keys supported by Hermes
+var sortKeyNames = []string{"time", "observer_type", "target_type", "target_id", "initiator_type", "initiator_id", "outcome", "action"}
+
+// addFilterFlags registers the flags, which filter events by their
+// attributes. All commands querying events register the same flags.
+func addFilterFlags(flags *pflag.FlagSet) {
+	flags.StringP("target-type", "", "", "filter events by a target type")
+	flags.StringP("target-id", "", "", "filter events by a target ID")
+	flags.StringP("initiator-type", "", "", "filter events by an initiator type")
+	flags.StringP("initiator-id", "", "", "filter events by an initiator ID")
+	flags.StringP("initiator-name", "", "", "filter events by an initiator name")
+	flags.StringP("action", "", "", "filter events by an action")
+	flags.StringP("outcome", "", "", "filter events by an outcome")
+	flags.StringP("request-path", "", "", "filter events by a request path")
+	flags.StringP("source", "", "", "filter events by a source")
+	flags.StringP("search", "", "", "filter events by a search string")
+	addScopeFlags(flags, "filter events by")
+}
+
+// addScopeFlags registers the flags, which scope a request to a project or a
+// domain. The action describes the request in the flag usages.
+func addScopeFlags(flags *pflag.FlagSet, action string) {
+	flags.StringP("project-id", "", "", action+" the project or domain ID (admin only)")
+	flags.BoolP("all-projects", "A", false, "include all projects and domains (admin only) (alias for --project-id '*')")
+	flags.StringP("domain-id", "", "", action+" the domain ID (admin only)")
+	flags.BoolP("all-domains", "", false, "include all domains (admin only) (alias for --domain-id '*')")
+}
+
+// verifyScopeFlags verifies that at most one of the scope flags is set.
+// Flags, which are not registered by the command, are empty.
+func verifyScopeFlags() error {
+	var set []string
+	if viper.GetString("project-id") != "" {
+		set = append(set, "--project-id")
+	}
+	if viper.GetBool("all-projects") {
+		set = append(set, "--all-projects")
+	}
+	if viper.GetString("domain-id") != "" {
+		set = append(set, "--domain-id")
+	}
+	if viper.GetBool("all-domains") {
+		set = append(set, "--all-domains")
+	}
+	if len(set) > 1 {
+		return fmt.Errorf("%s and %s cannot be both specified", set[0], set[1])
+	}
+	return nil
+}
+
+// getScope returns the project ID and the domain ID of the scope flags.
+func getScope() (projectID, domainID string) {
+	projectID = viper.GetString("project-id")
+	if viper.GetBool("all-projects") {
+		projectID = "*"
+	}
+	domainID = viper.GetString("domain-id")
+	if viper.GetBool("all-domains") {
+		domainID = "*"
+	}
+	return projectID, domainID
+}
+
+// addTimeFilterFlags registers the flags, which filter events by their time.
+func addTimeFilterFlags(flags *pflag.FlagSet) {
+	flags.StringP("time", "", "", "filter events by time")
+	flags.StringP("time-start", "", "", "filter events from time, e.g. 2024-05-01, -7d or yesterday")
+	flags.StringP("time-end", "", "", "filter events till time, e.g. 2024-05-01T12:00:00 or now")
+	flags.StringP("since", "", "", "filter events from duration ago, e.g. 2h (alias for --time-start)")
+}
+
+// addSortFlag registers the flag, which sorts the events.
+func addSortFlag(flags *pflag.FlagSet) {
+	flags.StringSliceP("sort", "s", []string{}, `supported sort keys include time, observer_type, target_type, target_id, initiator_type, initiator_id, outcome and action
+each sort key may also include a direction suffix
+supported directions are ":asc" for ascending and ":desc" for descending
+can be specified multiple times`)
+}
+
+// verifySortKeys verifies the sort keys and their directions.
+func verifySortKeys(keys []string) error {
+	for _, key := range keys {
+		name, direction, hasDirection := strings.Cut(key, ":")
+		if !slices.Contains(sortKeyNames, name) {
+			return fmt.Errorf("invalid %q sort key, supported sort keys: %s", name, strings.Join(sortKeyNames, ", "))
+		}
+		if hasDirection && direction != "asc" && direction != "desc" {
+			return fmt.Errorf("invalid %q sort direction of the %s sort key, supported directions: asc, desc", direction, name)
+		}
+	}
+	return nil
+}
+
+// verifyFilterFlags verifies the filter, time and sort flags. Flags, which
+// are not registered by the command, are empty.
+func verifyFilterFlags() error {
+	if err := verifyTimeFlags(); err != nil {
+		return err
+	}
+	return verifySortKeys(viper.GetStringSlice("sort"))
+}
+
+// buildFilterListOpts returns the list options of the attribute filter flags.
+func buildFilterListOpts() events.ListOpts {
+	projectID, domainID := getScope()
+
+	return events.ListOpts{
+		Limit:         maxOffset,
+		TargetType:    viper.GetString("target-type"),
+		TargetID:      viper.GetString("target-id"),
+		InitiatorType: viper.GetString("initiator-type"),
+		InitiatorID:   viper.GetString("initiator-id"),
+		InitiatorName: viper.GetString("initiator-name"),
+		Action:        viper.GetString("action"),
+		Outcome:       viper.GetString("outcome"),
+		RequestPath:   viper.GetString("request-path"),
+		ObserverType:  viper.GetString("source"),
+		Search:        viper.GetString("search"),
+		ProjectID:     projectID,
+		DomainID:      domainID,
+	}
+}
+
+// buildListOpts returns the list options of the attribute filter, time and
+// sort flags.
+func buildListOpts(now time.Time) (events.ListOpts, error) {
+	listOpts := buildFilterListOpts()
+	listOpts.Sort = strings.Join(viper.GetStringSlice("sort"), ",")
+
+	var err error
+	listOpts.Time, err = buildTimeFilter(now)
+	if err != nil {
+		return listOpts, err
+	}
+
+	return listOpts, nil
+}
+
+// applyUserLimit uses the user limit as the page size, when all events fit
+// into one page. Otherwise the maximum page size is kept and the user limit
+// is applied while fetching.
+func applyUserLimit(listOpts *events.ListOpts, userLimit int) {
+	if userLimit > 0 && userLimit <= maxOffset {
+		listOpts.Limit = userLimit
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func TestFilterFlagParity(t *testing.T) {
+	filters := pflag.NewFlagSet("filters", pflag.ContinueOnError)
+	addFilterFlags(filters)
+	addTimeFilterFlags(filters)
+	addSortFlag(filters)
+
+	filters.VisitAll(func(expected *pflag.Flag) {
+		for _, cmd := range []*cobra.Command{ListCmd, ExportCmd} {
+			f := cmd.Flags().Lookup(expected.Name)
+			if f == nil {
+				t.Errorf("%s command does not register the %s flag", cmd.Name(), expected.Name)
+				continue
+			}
+			if f.Usage != expected.Usage || f.Shorthand != expected.Shorthand || f.DefValue != expected.DefValue {
+				t.Errorf("%s command registers a different %s flag", cmd.Name(), expected.Name)
+			}
+		}
+	})
+}
+
+func TestVerifySortKeys(t *testing.T) {
+	for _, keys := range [][]string{nil, {"time"}, {"action:asc", "time:desc"}, {"initiator_id", "target_type:desc"}} {
+		if err := verifySortKeys(keys); err != nil {
+			t.Errorf("expected %v to be valid: %s", keys, err)
+		}
+	}
+	for _, keys := range [][]string{{"name"}, {"time:up"}, {"action", "initiator_name"}, {""}} {
+		if err := verifySortKeys(keys); err == nil {
+			t.Errorf("expected %v to fail", keys)
+		}
+	}
+}
+
+func TestApplyUserLimit(t *testing.T) {
+	cases := map[int]int{
+		0:         maxOffset,
+		50:        50,
+		maxOffset: maxOffset,
+		50000:     maxOffset,
+	}
+	for userLimit, expected := range cases {
+		listOpts := events.ListOpts{Limit: maxOffset}
+		applyUserLimit(&listOpts, userLimit)
+		if listOpts.Limit != expected {
+			t.Errorf("expected the %d user limit to use a page size of %d, got %d", userLimit, expected, listOpts.Limit)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/viper"
+)
+
+// fromFileIncompatibleFlags cannot be combined with the from-file flag,
+// because they require Hermes
+var fromFileIncompatibleFlags = []string{"cache", "offline"}
+
+// splitEventFileLocation returns the location of the directory and the name
+// of the export file. The location is a local path, a file:// URL or a
+// swift://container/object URL.
+func splitEventFileLocation(location string) (dir, name string, err error) {
+	if rest, ok := strings.CutPrefix(location, "swift://"); ok {
+		dir, name = path.Split(rest)
+		if dir == "" || name == "" {
+			return "", "", fmt.Errorf("invalid %q export object, expected swift://container/object", location)
+		}
+		return "swift://" + dir, name, nil
+	}
+
+	p := filepath.FromSlash(strings.TrimPrefix(location, "file://"))
+	dir, name = filepath.Split(p)
+	if name == "" {
+		return "", "", fmt.Errorf("invalid %q export file", location)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	return "file://" + filepath.ToSlash(dir), name, nil
+}
+
+// readEventFile reads the events of a local export file or of a Swift export
+// object.
+func readEventFile(ctx context.Context, location string, identities []age.Identity) ([]events.Event, error) {
+	dir, name, err := splitEventFileLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	fileType, ok := parseExportFileType(name)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an export file, supported extensions: %s", name, strings.Join(exportFileExtensions(), ", "))
+	}
+
+	destination, err := openExportLocation(ctx, dir, "")
+	if err != nil {
+		return nil, err
+	}
+	r, err := openExportFile(ctx, destination, name, identities)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	allEvents, err := readExportedEvents(r, fileType.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return allEvents, nil
+}
+
+// matchTime returns true, when the event time matches the time filters.
+// Exact time filters match the whole second like in Hermes.
+func matchTime(evt events.Event, filter []events.DateQuery) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	t, err := parseTime(evt.EventTime)
+	if err != nil {
+		return false
+	}
+	t = t.Truncate(time.Second)
+	for _, v := range filter {
+		d := v.Date.Truncate(time.Second)
+		var ok bool
+		switch v.Filter {
+		case events.DateFilterGT:
+			ok = t.After(d)
+		case events.DateFilterGTE:
+			ok = !t.Before(d)
+		case events.DateFilterLT:
+			ok = t.Before(d)
+		case events.DateFilterLTE:
+			ok = !t.After(d)
+		default:
+			ok = t.Equal(d)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEvent returns true, when the event matches the filters of the list
+// options. The search filter is a case insensitive substring search over the
+// whole event.
+func matchEvent(evt events.Event, listOpts events.ListOpts) bool {
+	filters := []struct {
+		Filter string
+		Value  string
+	}{
+		{listOpts.ObserverType, evt.Observer.TypeURI},
+		{listOpts.TargetType, evt.Target.TypeURI},
+		{listOpts.TargetID, evt.Target.ID},
+		{listOpts.InitiatorType, evt.Initiator.TypeURI},
+		{listOpts.InitiatorID, evt.Initiator.ID},
+		{listOpts.InitiatorName, evt.Initiator.Name},
+		{listOpts.Action, string(evt.Action)},
+		{listOpts.Outcome, string(evt.Outcome)},
+		{listOpts.RequestPath, evt.RequestPath},
+	}
+	for _, f := range filters {
+		if f.Filter != "" && f.Filter != f.Value {
+			return false
+		}
+	}
+
+	if p := listOpts.ProjectID; p != "" && p != "*" && p != evt.Initiator.ProjectID && p != evt.Target.ProjectID {
+		return false
+	}
+	if d := listOpts.DomainID; d != "" && d != "*" && d != evt.Initiator.DomainID && d != evt.Target.DomainID {
+		return false
+	}
+
+	if !matchTime(evt, listOpts.Time) {
+		return false
+	}
+
+	if listOpts.Search != "" {
+		data, err := json.Marshal(evt)
+		if err != nil || !strings.Contains(strings.ToLower(string(data)), strings.ToLower(listOpts.Search)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterEvents returns the events matching the list options in the requested
+// sort order.
+func filterEvents(allEvents []events.Event, listOpts events.ListOpts) []events.Event {
+	var result []events.Event
+	for _, evt := range allEvents {
+		if matchEvent(evt, listOpts) {
+			result = append(result, evt)
+		}
+	}
+	sortEvents(result, parseSortKeys(listOpts.Sort))
+	return result
+}
+
+// listFromFile prints the events of an export file, which match the list
+// options.
+func listFromFile(ctx context.Context, location string, listOpts events.ListOpts, userLimit int, format string, keyOrder []string) error {
+	var identities []age.Identity
+	if identityFiles, passphraseCmd := viper.GetStringSlice("identity"), viper.GetString("passphrase-cmd"); len(identityFiles) > 0 || passphraseCmd != "" {
+		var err error
+		identities, err = loadIdentities(identityFiles, passphraseCmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	allEvents, err := readEventFile(ctx, location, identities)
+	if err != nil {
+		return err
+	}
+
+	allEvents = filterEvents(allEvents, listOpts)
+	if userLimit > 0 && len(allEvents) > userLimit {
+		allEvents = allEvents[:userLimit]
+	}
+
+	return printEvent(allEvents, format, keyOrder)
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestSplitEventFileLocation(t *testing.T) {
+	cases := []struct {
+		Location string
+		Dir      string
+		Name     string
+	}{
+		{"export.json", "file://.", "export.json"},
+		{"/tmp/exports/export.csv.gz", "file:///tmp/exports/", "export.csv.gz"},
+		{"file:///tmp/exports/export.yaml", "file:///tmp/exports/", "export.yaml"},
+		{"swift://audit/export.json.gz", "swift://audit/", "export.json.gz"},
+		{"swift://audit/2024/05/export.json", "swift://audit/2024/05/", "export.json"},
+	}
+	for _, c := range cases {
+		dir, name, err := splitEventFileLocation(c.Location)
+		if err != nil {
+			t.Errorf("failed to split %q: %s", c.Location, err)
+			continue
+		}
+		if dir != c.Dir || name != c.Name {
+			t.Errorf("expected %q to be split into %q and %q, got %q and %q", c.Location, c.Dir, c.Name, dir, name)
+		}
+	}
+
+	for _, location := range []string{"swift://audit", "swift://audit/", "/tmp/exports/"} {
+		if _, _, err := splitEventFileLocation(location); err == nil {
+			t.Errorf("expected %q to fail", location)
+		}
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	allEvents := []events.Event{
+		{ID: "1", EventTime: "2024-01-15T09:00:00.000+0000", Action: cadf.Action("create"), Outcome: cadf.Outcome("success"), Initiator: cadf.Resource{Name: "admin"}, Target: cadf.Resource{TypeURI: "compute/server", ID: "vm-1"}},
+		{ID: "2", EventTime: "2024-01-15T10:00:00.000+0000", Action: cadf.Action("delete"), Outcome: cadf.Outcome("failure"), Initiator: cadf.Resource{Name: "admin"}, Target: cadf.Resource{TypeURI: "compute/server", ID: "vm-1"}},
+		{ID: "3", EventTime: "2024-01-15T11:00:00.000+0000", Action: cadf.Action("delete"), Outcome: cadf.Outcome("success"), Initiator: cadf.Resource{TypeURI: "service/security/account/user", Name: "operator", DomainID: "d-1"}, Target: cadf.Resource{TypeURI: "network/port", ID: "port-1"}},
+	}
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	cases := []struct {
+		Name     string
+		ListOpts events.ListOpts
+		Expected []string
+	}{
+		{"all", events.ListOpts{}, []string{"3", "2", "1"}},
+		{"action", events.ListOpts{Action: "delete"}, []string{"3", "2"}},
+		{"outcome and initiator", events.ListOpts{Outcome: "success", InitiatorName: "admin"}, []string{"1"}},
+		{"target", events.ListOpts{TargetType: "compute/server", TargetID: "vm-1", Sort: "time:asc"}, []string{"1", "2"}},
+		{"search", events.ListOpts{Search: "PORT-1"}, []string{"3"}},
+		{"initiator type", events.ListOpts{InitiatorType: "service/security/account/user"}, []string{"3"}},
+		{"domain", events.ListOpts{DomainID: "d-1"}, []string{"3"}},
+		{"all domains", events.ListOpts{DomainID: "*"}, []string{"3", "2", "1"}},
+		{"time range", events.ListOpts{Time: []events.DateQuery{
+			{Date: at("2024-01-15T09:30:00Z"), Filter: events.DateFilterGTE},
+			{Date: at("2024-01-15T10:00:00Z"), Filter: events.DateFilterLTE},
+		}}, []string{"2"}},
+		{"exact time", events.ListOpts{Time: []events.DateQuery{{Date: at("2024-01-15T11:00:00Z")}}}, []string{"3"}},
+		{"sort", events.ListOpts{Sort: "initiator_name:desc,time:asc"}, []string{"3", "1", "2"}},
+	}
+	for _, c := range cases {
+		var ids []string
+		for _, evt := range filterEvents(allEvents, c.ListOpts) {
+			ids = append(ids, evt.ID)
+		}
+		if !slices.Equal(ids, c.Expected) {
+			t.Errorf("%s: expected %v, got %v", c.Name, c.Expected, ids)
+		}
+	}
+}
+
+func TestReadEventFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.json")
+	if err := os.WriteFile(path, []byte(`[{"id":"1"},{"id":"2"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	allEvents, err := readEventFile(context.Background(), path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allEvents) != 2 {
+		t.Errorf("expected 2 events, got %d", len(allEvents))
+	}
+
+	if _, err := readEventFile(context.Background(), filepath.Join(dir, "export.txt"), nil); err == nil {
+		t.Error("expected an unsupported extension to fail")
+	}
+}
@@ -0,0 +1,340 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var historyPrintFormats = []string{
+	"table",
+	"json",
+	"yaml",
+	"csv",
+}
+
+// lifecycleActions are the actions, which change the state of a resource
+var lifecycleActions = []cadf.Action{
+	cadf.CreateAction,
+	cadf.UpdateAction,
+	cadf.DeleteAction,
+	cadf.StartAction,
+	cadf.StopAction,
+}
+
+// historyEntry is a single entry of the resource history. Consecutive reads
+// of the same initiator with the same outcome are collapsed into one entry.
+type historyEntry struct {
+	EventID        string   `json:"event_id" yaml:"event_id"`
+	Time           string   `json:"time" yaml:"time"`
+	LastTime       string   `json:"last_time,omitempty" yaml:"last_time,omitempty"`
+	Count          int      `json:"count" yaml:"count"`
+	Action         string   `json:"action" yaml:"action"`
+	Outcome        string   `json:"outcome" yaml:"outcome"`
+	Initiator      string   `json:"initiator" yaml:"initiator"`
+	Lifecycle      bool     `json:"lifecycle" yaml:"lifecycle"`
+	Failure        bool     `json:"failure" yaml:"failure"`
+	AttachmentDiff []string `json:"attachment_diff,omitempty" yaml:"attachment_diff,omitempty"`
+}
+
+// Highlights returns the highlights of the entry, e.g. "lifecycle".
+func (e historyEntry) Highlights() string {
+	var result []string
+	if e.Lifecycle {
+		result = append(result, "lifecycle")
+	}
+	if e.Failure {
+		result = append(result, "failure")
+	}
+	return strings.Join(result, ", ")
+}
+
+// hasBaseAction returns true, when the action is the base action or one of
+// its sub actions, e.g. "update/add" for "update".
+func hasBaseAction(action, base cadf.Action) bool {
+	return action == base || strings.HasPrefix(string(action), string(base)+"/")
+}
+
+func isReadAction(action cadf.Action) bool {
+	return hasBaseAction(action, cadf.ReadAction) || hasBaseAction(action, cadf.ListAction)
+}
+
+func isLifecycleAction(action cadf.Action) bool {
+	return slices.ContainsFunc(lifecycleActions, func(base cadf.Action) bool {
+		return hasBaseAction(action, base)
+	})
+}
+
+// flattenValue adds the scalar values of the JSON value to the result. Keys
+// of nested objects are joined with dots, array indexes are appended in
+// brackets.
+func flattenValue(result map[string]string, key string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, item := range v {
+			flattenValue(result, strings.TrimPrefix(key+"."+k, "."), item)
+		}
+	case []any:
+		for i, item := range v {
+			flattenValue(result, key+"["+strconv.Itoa(i)+"]", item)
+		}
+	case string:
+		// attachments usually contain serialized JSON
+		var parsed any
+		if err := json.Unmarshal([]byte(v), &parsed); err == nil {
+			switch parsed.(type) {
+			case map[string]any, []any:
+				flattenValue(result, key, parsed)
+				return
+			}
+		}
+		result[key] = v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			result[key] = fmt.Sprintf("%v", v)
+			return
+		}
+		result[key] = string(data)
+	}
+}
+
+// flattenAttachments returns the flattened contents of the event and the
+// target attachments. Keys start with the attachment name.
+func flattenAttachments(evt events.Event) map[string]string {
+	result := make(map[string]string)
+	attachments := slices.Concat(evt.Attachments, evt.Target.Attachments)
+	for i, attachment := range attachments {
+		// normalize the content to generic JSON values
+		var content any
+		data, err := json.Marshal(attachment.Content)
+		if err == nil {
+			err = json.Unmarshal(data, &content)
+		}
+		if err != nil {
+			content = fmt.Sprintf("%v", attachment.Content)
+		}
+		flattenValue(result, cmp.Or(attachment.Name, "attachment["+strconv.Itoa(i)+"]"), content)
+	}
+	return result
+}
+
+// diffAttachments returns the added ("+"), removed ("-") and changed ("~")
+// values of the flattened attachments sorted by their keys.
+func diffAttachments(previous, current map[string]string) []string {
+	keys := slices.Collect(maps.Keys(previous))
+	for k := range current {
+		if _, ok := previous[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var result []string
+	for _, k := range keys {
+		oldValue, hasOld := previous[k]
+		newValue, hasNew := current[k]
+		switch {
+		case !hasOld:
+			result = append(result, fmt.Sprintf("+ %s: %s", k, newValue))
+		case !hasNew:
+			result = append(result, fmt.Sprintf("- %s: %s", k, oldValue))
+		case oldValue != newValue:
+			result = append(result, fmt.Sprintf("~ %s: %s -> %s", k, oldValue, newValue))
+		}
+	}
+	return result
+}
+
+// buildHistory converts the chronologically ordered events of a resource into
+// history entries. With diff, the attachments of each successful update are
+// compared with the previous successful create or update.
+func buildHistory(allEvents []events.Event, collapseReads, diff bool) []historyEntry {
+	var result []historyEntry
+	var previous map[string]string
+	for _, evt := range allEvents {
+		initiator := cmp.Or(evt.Initiator.Name, evt.Initiator.ID)
+		failure := evt.Outcome == cadf.FailureOutcome
+
+		if collapseReads && isReadAction(evt.Action) && len(result) > 0 {
+			last := &result[len(result)-1]
+			if last.Action == string(evt.Action) && last.Initiator == initiator && last.Failure == failure {
+				last.Count++
+				last.LastTime = evt.EventTime
+				continue
+			}
+		}
+
+		entry := historyEntry{
+			EventID:   evt.ID,
+			Time:      evt.EventTime,
+			Count:     1,
+			Action:    string(evt.Action),
+			Outcome:   string(evt.Outcome),
+			Initiator: initiator,
+			Lifecycle: isLifecycleAction(evt.Action),
+			Failure:   failure,
+		}
+		if diff && !failure && (hasBaseAction(evt.Action, cadf.CreateAction) || hasBaseAction(evt.Action, cadf.UpdateAction)) {
+			current := flattenAttachments(evt)
+			if previous != nil && hasBaseAction(evt.Action, cadf.UpdateAction) {
+				entry.AttachmentDiff = diffAttachments(previous, current)
+			}
+			previous = current
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func printHistory(entries []historyEntry, format string, diff bool) error {
+	header := []string{"Time", "Last Time", "Count", "Action", "Outcome", "Initiator", "Highlights"}
+	if diff {
+		header = append(header, "Attachment Diff")
+	}
+
+	records := make([][]string, len(entries))
+	for i, e := range entries {
+		var lastTime string
+		if e.LastTime != "" {
+			lastTime = formatEventTime(e.LastTime)
+		}
+		records[i] = []string{formatEventTime(e.Time), lastTime, strconv.Itoa(e.Count), e.Action, e.Outcome, e.Initiator, e.Highlights()}
+		if diff {
+			records[i] = append(records[i], strings.Join(e.AttachmentDiff, "\n"))
+		}
+	}
+
+	switch format {
+	case "table":
+		table := tablewriter.NewTable(os.Stdout,
+			tablewriter.WithRowAlignment(tw.AlignRight),
+		)
+		table.Header(header)
+		if err := table.Bulk(records); err != nil {
+			return fmt.Errorf("error appending rows to table: %w", err)
+		}
+		if err := table.Render(); err != nil {
+			return fmt.Errorf("error rendering table: %w", err)
+		}
+	case "csv":
+		csvWriter := csv.NewWriter(os.Stdout)
+		if err := csvWriter.Write(header); err != nil {
+			return fmt.Errorf("error writing header to csv: %w", err)
+		}
+		if err := csvWriter.WriteAll(records); err != nil {
+			return fmt.Errorf("error writing records to csv: %w", err)
+		}
+	case "json":
+		jsonHistory, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", jsonHistory)
+	case "yaml":
+		yamlHistory, err := yaml.Marshal(entries)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s", yamlHistory)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return nil
+}
+
+// HistoryCmd represents the history command
+var HistoryCmd = &cobra.Command{
+	Use:   "history <target-id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Show the history of a resource",
+	Long: `Show all events of a target resource in chronological order.
+Consecutive reads of the same initiator are collapsed, lifecycle transitions (create, update, delete, start, stop)
+and failures are highlighted.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		if err := verifyTimeFlags(); err != nil {
+			return err
+		}
+
+		return verifyGlobalFlags(nil, historyPrintFormats...)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		timeFilter, err := buildTimeFilter(time.Now())
+		if err != nil {
+			return err
+		}
+		projectID, domainID := getScope()
+		listOpts := events.ListOpts{
+			Limit:     maxOffset,
+			TargetID:  args[0],
+			ProjectID: projectID,
+			DomainID:  domainID,
+			Time:      timeFilter,
+			Sort:      "time:asc",
+		}
+
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+
+		var allEvents []events.Event
+		var bar *pb.ProgressBar
+		err = getEvents(ctx, client, listOpts, 0, viper.GetInt("concurrency"), &bar, func(page []events.Event) error {
+			allEvents = append(allEvents, page...)
+			return nil
+		})
+		if bar != nil {
+			bar.Finish()
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list the events of the %s target: %w", args[0], err)
+		}
+		if len(allEvents) == 0 {
+			return fmt.Errorf("no events of the %s target found", args[0])
+		}
+
+		diff := viper.GetBool("diff")
+		entries := buildHistory(allEvents, !viper.GetBool("all-reads"), diff)
+		return printHistory(entries, viper.GetString("format"), diff)
+	},
+}
+
+func init() {
+	initHistoryCmdFlags()
+	RootCmd.AddCommand(HistoryCmd)
+}
+
+func initHistoryCmdFlags() {
+	addScopeFlags(HistoryCmd.Flags(), "show the history in")
+	addTimeFilterFlags(HistoryCmd.Flags())
+	HistoryCmd.Flags().BoolP("all-reads", "", false, "print every read instead of collapsing consecutive reads of the same initiator")
+	HistoryCmd.Flags().BoolP("diff", "", false, "print the changes of the attachments between consecutive updates")
+	HistoryCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
+}
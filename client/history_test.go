@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestBuildHistory(t *testing.T) {
+	event := func(id string, action cadf.Action, outcome cadf.Outcome, initiator, payload string) events.Event {
+		evt := events.Event{
+			ID:        id,
+			EventTime: "2024-01-15T09:00:0" + id + ".000+0000",
+			Action:    action,
+			Outcome:   outcome,
+			Initiator: cadf.Resource{Name: initiator},
+		}
+		if payload != "" {
+			evt.Attachments = []cadf.Attachment{{Name: "payload", TypeURI: "mime:application/json", Content: payload}}
+		}
+		return evt
+	}
+	allEvents := []events.Event{
+		event("1", cadf.CreateAction, cadf.SuccessOutcome, "admin", `{"name":"vm","size":1}`),
+		event("2", cadf.ReadAction, cadf.SuccessOutcome, "admin", ""),
+		event("3", cadf.ReadAction, cadf.SuccessOutcome, "admin", ""),
+		event("4", cadf.ReadAction, cadf.SuccessOutcome, "monitor", ""),
+		event("5", "update/set", cadf.FailureOutcome, "admin", `{"name":"broken"}`),
+		event("6", cadf.UpdateAction, cadf.SuccessOutcome, "admin", `{"name":"vm","size":2,"tags":["a"]}`),
+		event("7", cadf.DeleteAction, cadf.SuccessOutcome, "admin", ""),
+	}
+
+	entries := buildHistory(allEvents, true, true)
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.EventID)
+	}
+	if expected := []string{"1", "2", "4", "5", "6", "7"}; !slices.Equal(ids, expected) {
+		t.Fatalf("expected %v entries, got %v", expected, ids)
+	}
+
+	if e := entries[1]; e.Count != 2 || e.LastTime != allEvents[2].EventTime {
+		t.Errorf("expected the reads to be collapsed, got %+v", e)
+	}
+	if e := entries[3]; !e.Lifecycle || !e.Failure || e.AttachmentDiff != nil || e.Highlights() != "lifecycle, failure" {
+		t.Errorf("expected a failed lifecycle transition without a diff, got %+v", e)
+	}
+	expectedDiff := []string{"~ payload.size: 1 -> 2", "+ payload.tags[0]: a"}
+	if e := entries[4]; !slices.Equal(e.AttachmentDiff, expectedDiff) {
+		t.Errorf("expected the %v diff, got %v", expectedDiff, e.AttachmentDiff)
+	}
+
+	if n := len(buildHistory(allEvents, false, false)); n != len(allEvents) {
+		t.Errorf("expected %d entries without collapsed reads, got %d", len(allEvents), n)
+	}
+}
@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"go.xyrillian.de/schwift/v2"
+)
+
+// defaultExportStateName is the name of the incremental export state object
+// or file next to the exported files
+const defaultExportStateName = "hermes-export.state.json"
+
+// exportState is the persisted watermark of incremental exports. IDs contains
+// the events of the watermark second, which were already exported.
+type exportState struct {
+	Time       time.Time `json:"time"`
+	IDs        []string  `json:"ids"`
+	ExportedAt time.Time `json:"exported_at"`
+	FileName   string    `json:"file_name"`
+}
+
+func (s exportState) Watermark() *eventWatermark {
+	w := &eventWatermark{
+		Time: s.Time,
+		IDs:  make(map[string]struct{}, len(s.IDs)),
+	}
+	for _, id := range s.IDs {
+		w.IDs[id] = struct{}{}
+	}
+	return w
+}
+
+func newExportState(w *eventWatermark, fileName string) exportState {
+	return exportState{
+		Time:       w.Time.UTC(),
+		IDs:        slices.Sorted(maps.Keys(w.IDs)),
+		ExportedAt: time.Now().UTC(),
+		FileName:   fileName,
+	}
+}
+
+// capTimeFilter limits the time filter to events before the settle bound.
+// Hermes may index events late, events newer than the bound are exported by
+// a later incremental export, once they are settled. Earlier end filters are
+// kept.
+func capTimeFilter(filter []events.DateQuery, bound time.Time) []events.DateQuery {
+	result := slices.DeleteFunc(slices.Clone(filter), func(q events.DateQuery) bool {
+		switch q.Filter {
+		case events.DateFilterLT:
+			return !q.Date.Before(bound)
+		case events.DateFilterLTE:
+			return !q.Date.Truncate(time.Second).Add(time.Second).Before(bound)
+		}
+		return false
+	})
+	if !slices.ContainsFunc(result, func(q events.DateQuery) bool {
+		return q.Filter == events.DateFilterLT || q.Filter == events.DateFilterLTE
+	}) {
+		result = append(result, events.DateQuery{Date: bound, Filter: events.DateFilterLT})
+	}
+	return result
+}
+
+// exportStateStore persists the state of incremental exports
+type exportStateStore interface {
+	// Load returns nil, when no state was saved yet
+	Load(ctx context.Context) (*exportState, error)
+	Save(ctx context.Context, state exportState) error
+	Location() string
+}
+
+func unmarshalExportState(data []byte) (*exportState, error) {
+	var state exportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse export state: %w", err)
+	}
+	return &state, nil
+}
+
+// swiftStateStore stores the export state as a Swift object
+type swiftStateStore struct {
+	Object *schwift.Object
+}
+
+func (s swiftStateStore) Load(ctx context.Context) (*exportState, error) {
+	data, err := s.Object.Download(ctx, nil).AsByteSlice()
+	if schwift.Is(err, http.StatusNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to download export state: %w", err)
+	}
+	return unmarshalExportState(data)
+}
+
+func (s swiftStateStore) Save(ctx context.Context, state exportState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export state: %w", err)
+	}
+	headers := make(schwift.Headers)
+	headers.Set("Content-Type", "application/json")
+	if err := s.Object.Upload(ctx, bytes.NewReader(data), nil, headers.ToOpts()); err != nil {
+		return fmt.Errorf("failed to upload export state: %w", err)
+	}
+	return nil
+}
+
+func (s swiftStateStore) Location() string {
+	return s.Object.FullName()
+}
+
+// fileStateStore stores the export state in a local file
+type fileStateStore struct {
+	Path string
+}
+
+func (s fileStateStore) Load(ctx context.Context) (*exportState, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export state: %w", err)
+	}
+	return unmarshalExportState(data)
+}
+
+func (s fileStateStore) Save(ctx context.Context, state exportState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal export state: %w", err)
+	}
+	if err := writeFileAtomic(s.Path, data); err != nil {
+		return fmt.Errorf("failed to write export state: %w", err)
+	}
+	return nil
+}
+
+func (s fileStateStore) Location() string {
+	return s.Path
+}
+
+// getExportStateStore returns the state store of incremental exports. A state
+// file takes precedence over the state next to the exported files.
+func getExportStateStore(destination exportDestination, stateFile string) (exportStateStore, error) {
+	if stateFile != "" {
+		return fileStateStore{Path: stateFile}, nil
+	}
+	if store := destination.StateStore(defaultExportStateName); store != nil {
+		return store, nil
+	}
+	return nil, errors.New("--state-file is required for incremental exports to stdout")
+}
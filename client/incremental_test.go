@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestFileStateStore(t *testing.T) {
+	ctx := context.Background()
+	store := fileStateStore{Path: filepath.Join(t.TempDir(), defaultExportStateName)}
+
+	state, err := store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != nil {
+		t.Fatalf("expected no state, got %+v", state)
+	}
+
+	w := &eventWatermark{}
+	exported := w.Filter([]events.Event{
+		{ID: "a", EventTime: "2024-05-01T10:00:00+0000"},
+		{ID: "b", EventTime: "2024-05-01T10:00:01.123+0000"},
+		{ID: "c", EventTime: "2024-05-01T10:00:01.456+0000"},
+	})
+	if len(exported) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(exported))
+	}
+	if err := store.Save(ctx, newExportState(w, "hermes-export.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = store.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == nil {
+		t.Fatal("expected a state")
+	}
+
+	// the next export starts at the watermark second and skips the exported
+	// events of that second
+	next := state.Watermark().Filter([]events.Event{
+		{ID: "b", EventTime: "2024-05-01T10:00:01.123+0000"},
+		{ID: "c", EventTime: "2024-05-01T10:00:01.456+0000"},
+		{ID: "d", EventTime: "2024-05-01T10:00:01.789+0000"},
+		{ID: "e", EventTime: "2024-05-01T10:00:02+0000"},
+	})
+	if len(next) != 2 || next[0].ID != "d" || next[1].ID != "e" {
+		t.Errorf("unexpected events after the watermark: %+v", next)
+	}
+	if !state.Time.Equal(time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC)) {
+		t.Errorf("unexpected watermark time: %s", state.Time)
+	}
+}
+
+func TestCapTimeFilter(t *testing.T) {
+	bound := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	start := events.DateQuery{Date: bound.Add(-time.Hour), Filter: events.DateFilterGTE}
+
+	cases := []struct {
+		Name     string
+		Filter   []events.DateQuery
+		Expected []events.DateQuery
+	}{
+		{
+			Name:     "no end filter",
+			Filter:   []events.DateQuery{start},
+			Expected: []events.DateQuery{start, {Date: bound, Filter: events.DateFilterLT}},
+		},
+		{
+			Name:     "end filter after the bound",
+			Filter:   []events.DateQuery{start, {Date: bound.Add(time.Minute), Filter: events.DateFilterLTE}},
+			Expected: []events.DateQuery{start, {Date: bound, Filter: events.DateFilterLT}},
+		},
+		{
+			Name:     "end filter within the bound second",
+			Filter:   []events.DateQuery{start, {Date: bound.Add(-time.Second), Filter: events.DateFilterLTE}},
+			Expected: []events.DateQuery{start, {Date: bound, Filter: events.DateFilterLT}},
+		},
+		{
+			Name:     "end filter before the bound",
+			Filter:   []events.DateQuery{start, {Date: bound.Add(-time.Minute), Filter: events.DateFilterLTE}},
+			Expected: []events.DateQuery{start, {Date: bound.Add(-time.Minute), Filter: events.DateFilterLTE}},
+		},
+	}
+	for _, c := range cases {
+		result := capTimeFilter(c.Filter, bound)
+		if !slices.EqualFunc(result, c.Expected, func(a, b events.DateQuery) bool {
+			return a.Filter == b.Filter && a.Date.Equal(b.Date)
+		}) {
+			t.Errorf("%s: expected %v, got %v", c.Name, c.Expected, result)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"filippo.io/age"
+	"github.com/sapcc/go-api-declarations/bininfo"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+// integrityManifestSuffix is appended to the object name of an export file to
+// get the name of its integrity manifest
+const integrityManifestSuffix = ".manifest"
+
+// integrityManifest proves the completeness and integrity of an export file.
+// It is stored next to the export file.
+type integrityManifest struct {
+	Object      string       `json:"object"`
+	Format      ExportFormat `json:"format"`
+	Compression Compression  `json:"compression,omitempty"`
+	// Encryption contains the recipients of encrypted objects
+	Encryption []string `json:"encryption,omitempty"`
+	// SHA256 and SizeBytes describe the stored, possibly compressed and
+	// encrypted, object
+	SHA256    string `json:"sha256"`
+	SizeBytes int64  `json:"size_bytes"`
+	// ContentSHA256 and ContentSizeBytes describe the plain content
+	ContentSHA256    string    `json:"content_sha256"`
+	ContentSizeBytes int64     `json:"content_size_bytes"`
+	Events           int       `json:"events"`
+	FirstEventTime   time.Time `json:"first_event_time"`
+	LastEventTime    time.Time `json:"last_event_time"`
+	// Filters are the filters of the whole export
+	Filters events.ListOpts `json:"filters"`
+	// HermesTotal is the amount of events reported by Hermes for the filters
+	// of the whole export
+	HermesTotal int       `json:"hermes_total"`
+	Version     string    `json:"hermescli_version"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// hashingReader computes the SHA-256 and the size of the read data
+type hashingReader struct {
+	Reader io.Reader
+	Hash   hash.Hash
+	Size   int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{Reader: r, Hash: sha256.New()}
+}
+
+func (hr *hashingReader) Read(p []byte) (n int, err error) {
+	n, err = hr.Reader.Read(p)
+	if n > 0 {
+		hr.Hash.Write(p[:n])
+		hr.Size += int64(n)
+	}
+	return
+}
+
+func (hr *hashingReader) Sum() string {
+	return hex.EncodeToString(hr.Hash.Sum(nil))
+}
+
+// eventTimeRange returns the time of the oldest and the newest event.
+func eventTimeRange(allEvents []events.Event) (first, last time.Time) {
+	for _, evt := range allEvents {
+		t, err := parseTime(evt.EventTime)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	return first, last
+}
+
+// newIntegrityManifest describes the uploaded export file.
+func newIntegrityManifest(f ExportFile, stored, content *hashingReader, fileEvents []events.Event, filters events.ListOpts, hermesTotal int) integrityManifest {
+	first, last := eventTimeRange(fileEvents)
+	return integrityManifest{
+		Object:           f.ObjectName(),
+		Format:           f.Format,
+		Compression:      f.Compression,
+		Encryption:       f.Encryption,
+		SHA256:           stored.Sum(),
+		SizeBytes:        stored.Size,
+		ContentSHA256:    content.Sum(),
+		ContentSizeBytes: content.Size,
+		Events:           len(fileEvents),
+		FirstEventTime:   first,
+		LastEventTime:    last,
+		Filters:          filters,
+		HermesTotal:      hermesTotal,
+		Version:          bininfo.VersionOr("unknown"),
+		CreatedAt:        time.Now().UTC(),
+	}
+}
+
+// uploadIntegrityManifest writes the manifest next to the export file.
+func uploadIntegrityManifest(ctx context.Context, destination exportDestination, m integrityManifest, segmentSize uint64) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal integrity manifest: %w", err)
+	}
+	f := ExportFile{
+		Format:      ExportFormatJSON,
+		FileName:    m.Object + integrityManifestSuffix,
+		SegmentSize: segmentSize,
+		Contents:    bytes.NewReader(data),
+	}
+	if err := destination.Upload(ctx, f); err != nil {
+		return fmt.Errorf("failed to write integrity manifest: %w", err)
+	}
+	return nil
+}
+
+// integrityManifestName returns the object name of the manifest of the
+// export file.
+func integrityManifestName(objectName string) string {
+	return objectName + integrityManifestSuffix + "." + string(ExportFormatJSON)
+}
+
+// loadIntegrityManifest reads the manifest of the export file. The SHA-256 of
+// the manifest is returned to verify its signature.
+func loadIntegrityManifest(ctx context.Context, destination exportDestination, objectName string) (integrityManifest, string, error) {
+	var m integrityManifest
+	r, err := destination.Open(ctx, integrityManifestName(objectName))
+	if err != nil {
+		return m, "", err
+	}
+	defer r.Close() //nolint:errcheck
+
+	hr := newHashingReader(r)
+	data, err := io.ReadAll(hr)
+	if err != nil {
+		return m, "", fmt.Errorf("failed to read integrity manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, "", fmt.Errorf("failed to parse integrity manifest: %w", err)
+	}
+	return m, hr.Sum(), nil
+}
+
+// integrityCheck is a single compared attribute of an export file
+type integrityCheck struct {
+	Name     string `json:"name"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	OK       bool   `json:"ok"`
+}
+
+// parseExportedEvents returns the amount of events and their time range in
+// the plain contents of an export file.
+func parseExportedEvents(r io.Reader, format ExportFormat) (count int, first, last time.Time, err error) {
+	allEvents, err := readExportedEvents(r, format)
+	if err != nil {
+		return 0, first, last, err
+	}
+	first, last = eventTimeRange(allEvents)
+	return len(allEvents), first, last, nil
+}
+
+// verifyExportFile downloads the export file and compares it with its
+// integrity manifest. The identities are required for encrypted files.
+func verifyExportFile(ctx context.Context, destination exportDestination, m integrityManifest, identities []age.Identity) ([]integrityCheck, error) {
+	r, err := destination.Open(ctx, m.Object)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	stored := newHashingReader(r)
+	var decrypted io.Reader = stored
+	if len(m.Encryption) > 0 {
+		decrypted, err = decryptReader(stored, identities)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", m.Object, err)
+		}
+	}
+	decompressed, err := newDecompressReader(decrypted, m.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", m.Object, err)
+	}
+	defer decompressed.Close() //nolint:errcheck
+	content := newHashingReader(decompressed)
+
+	count, first, last, err := parseExportedEvents(content, m.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.Object, err)
+	}
+	// hash the trailing data, which is not required for parsing
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.Object, err)
+	}
+	if _, err := io.Copy(io.Discard, stored); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.Object, err)
+	}
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	check := func(name, expected, actual string) integrityCheck {
+		return integrityCheck{Name: name, Expected: expected, Actual: actual, OK: expected == actual}
+	}
+
+	return []integrityCheck{
+		check("SHA-256", m.SHA256, stored.Sum()),
+		check("Size", fmt.Sprint(m.SizeBytes), fmt.Sprint(stored.Size)),
+		check("Content SHA-256", m.ContentSHA256, content.Sum()),
+		check("Content size", fmt.Sprint(m.ContentSizeBytes), fmt.Sprint(content.Size)),
+		check("Events", fmt.Sprint(m.Events), fmt.Sprint(count)),
+		check("First event time", formatTime(m.FirstEventTime), formatTime(first)),
+		check("Last event time", formatTime(m.LastEventTime), formatTime(last)),
+	}, nil
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestVerifyExportFile(t *testing.T) {
+	ctx := context.Background()
+	allEvents := []events.Event{
+		{ID: "a", EventTime: "2024-05-01T10:00:00+0000"},
+		{ID: "b", EventTime: "2024-05-01T12:30:00+0000"},
+	}
+
+	for _, format := range allExportFormats {
+		for _, compression := range allCompressions {
+			destination := fileDestination{Dir: t.TempDir()}
+			var buf bytes.Buffer
+			if err := convertToRequestedFormat(&buf, allEvents, string(format)); err != nil {
+				t.Fatal(err)
+			}
+
+			content := newHashingReader(&buf)
+			stored := content
+			f := ExportFile{Format: format, Compression: compression, FileName: "export"}
+			if compression == CompressionNone {
+				f.Contents = content
+			} else {
+				stored = newHashingReader(compressReader(content, compression))
+				f.Contents = stored
+			}
+			if err := destination.Upload(ctx, f); err != nil {
+				t.Fatal(err)
+			}
+			m := newIntegrityManifest(f, stored, content, allEvents, events.ListOpts{}, len(allEvents))
+			if err := uploadIntegrityManifest(ctx, destination, m, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			m, _, err := loadIntegrityManifest(ctx, destination, f.ObjectName())
+			if err != nil {
+				t.Fatal(err)
+			}
+			checks, err := verifyExportFile(ctx, destination, m, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range checks {
+				if !c.OK {
+					t.Errorf("%s: unexpected %s mismatch: expected %q, got %q", f.ObjectName(), c.Name, c.Expected, c.Actual)
+				}
+			}
+
+			// a truncated file must not pass the verification
+			path := destination.Location(f)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, data[:len(data)-1], 0o644); err != nil {
+				t.Fatal(err)
+			}
+			checks, err = verifyExportFile(ctx, destination, m, nil)
+			if err == nil && checks[0].OK {
+				t.Errorf("%s: expected a SHA-256 mismatch after truncation", filepath.Base(path))
+			}
+		}
+	}
+}
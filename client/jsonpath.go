@@ -0,0 +1,488 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStepKind is the kind of a single JSONPath step
+type jsonPathStepKind int
+
+const (
+	jsonPathField jsonPathStepKind = iota
+	jsonPathWildcard
+	jsonPathIndex
+	jsonPathSlice
+	jsonPathRecursive
+	jsonPathFilter
+)
+
+// jsonPathStep is a single step of a JSONPath expression, e.g. ".name",
+// "[0]" or "[?(@.outcome=="failure")]".
+type jsonPathStep struct {
+	Kind jsonPathStepKind
+	Name string
+	// Index is the index of an index step and the start of a slice step
+	Index int
+	End   *int
+	Start *int
+	// Filter, Op and Value are the path, the operator ("", "==" or "!=") and
+	// the compared value of a filter step
+	Filter []jsonPathStep
+	Op     string
+	Value  any
+}
+
+// jsonPathExpr is a parsed JSONPath expression. Root expressions start with
+// "$", other expressions are relative to the current item of a range.
+type jsonPathExpr struct {
+	Root  bool
+	Steps []jsonPathStep
+}
+
+// jsonPathNode is a literal text, an expression or a range of a JSONPath
+// template like kubectl supports it, e.g.
+// "{range .items[*]}{.id}{"\n"}{end}".
+type jsonPathNode struct {
+	Text  string
+	Expr  *jsonPathExpr
+	Range *jsonPathExpr
+	Body  []jsonPathNode
+}
+
+// jsonPathTemplate is a parsed JSONPath template.
+type jsonPathTemplate struct {
+	Nodes []jsonPathNode
+}
+
+// splitJSONPathTemplate splits the template into literal texts and
+// expressions in curly braces. Expressions are returned with their braces.
+func splitJSONPathTemplate(template string) ([]string, error) {
+	var result []string
+	var text strings.Builder
+	for i := 0; i < len(template); i++ {
+		if template[i] != '{' {
+			text.WriteByte(template[i])
+			continue
+		}
+		if text.Len() > 0 {
+			result = append(result, text.String())
+			text.Reset()
+		}
+
+		// find the closing brace outside of quotes
+		var quote byte
+		end := -1
+		for j := i + 1; j < len(template) && end < 0; j++ {
+			switch c := template[j]; {
+			case quote != 0 && c == '\\':
+				j++
+			case quote != 0 && c == quote:
+				quote = 0
+			case quote != 0:
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '}':
+				end = j
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed expression at offset %d", i)
+		}
+		result = append(result, template[i:end+1])
+		i = end
+	}
+	if text.Len() > 0 {
+		result = append(result, text.String())
+	}
+	return result, nil
+}
+
+// parseJSONPathTemplate parses a JSONPath template.
+func parseJSONPathTemplate(template string) (*jsonPathTemplate, error) {
+	parts, err := splitJSONPathTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	// stack of the open ranges, the first element is the template itself
+	stack := []*jsonPathNode{{}}
+	for _, part := range parts {
+		current := stack[len(stack)-1]
+		if !strings.HasPrefix(part, "{") {
+			current.Body = append(current.Body, jsonPathNode{Text: part})
+			continue
+		}
+
+		expr := strings.TrimSpace(part[1 : len(part)-1])
+		switch {
+		case expr == "end":
+			if len(stack) == 1 {
+				return nil, errors.New("{end} without {range}")
+			}
+			stack = stack[:len(stack)-1]
+			parent := stack[len(stack)-1]
+			parent.Body = append(parent.Body, *current)
+		case strings.HasPrefix(expr, "range "):
+			rangeExpr, err := parseJSONPathExpr(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, &jsonPathNode{Range: rangeExpr})
+		case strings.HasPrefix(expr, `"`) || strings.HasPrefix(expr, "'"):
+			text, err := unquoteJSONPath(expr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s literal: %w", expr, err)
+			}
+			current.Body = append(current.Body, jsonPathNode{Text: text})
+		default:
+			pathExpr, err := parseJSONPathExpr(expr)
+			if err != nil {
+				return nil, err
+			}
+			current.Body = append(current.Body, jsonPathNode{Expr: pathExpr})
+		}
+	}
+	if len(stack) > 1 {
+		return nil, errors.New("{range} without {end}")
+	}
+
+	return &jsonPathTemplate{Nodes: stack[0].Body}, nil
+}
+
+// unquoteJSONPath unquotes a single or double quoted string literal.
+func unquoteJSONPath(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", errors.New("unterminated string")
+		}
+		s = `"` + strings.ReplaceAll(s[1:len(s)-1], `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// parseJSONPathExpr parses a JSONPath expression like ".items[*].id".
+func parseJSONPathExpr(expr string) (*jsonPathExpr, error) {
+	result := &jsonPathExpr{}
+	s := expr
+	switch {
+	case strings.HasPrefix(s, "$"):
+		result.Root = true
+		s = s[1:]
+	case strings.HasPrefix(s, "@"):
+		s = s[1:]
+	}
+	if s != "" && s[0] != '.' && s[0] != '[' {
+		return nil, fmt.Errorf("invalid %q JSONPath expression: expected . or [", expr)
+	}
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := cutJSONPathName(s[2:])
+			if name == "" {
+				return nil, fmt.Errorf("invalid %q JSONPath expression: missing name after ..", expr)
+			}
+			result.Steps = append(result.Steps, jsonPathStep{Kind: jsonPathRecursive, Name: name})
+			s = rest
+		case s[0] == '.':
+			name, rest := cutJSONPathName(s[1:])
+			switch name {
+			case "":
+				// "." refers to the current item
+			case "*":
+				result.Steps = append(result.Steps, jsonPathStep{Kind: jsonPathWildcard})
+			default:
+				result.Steps = append(result.Steps, jsonPathStep{Kind: jsonPathField, Name: name})
+			}
+			s = rest
+		case s[0] == '[':
+			end := findJSONPathBracket(s)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid %q JSONPath expression: unclosed [", expr)
+			}
+			step, err := parseJSONPathBracket(s[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %q JSONPath expression: %w", expr, err)
+			}
+			result.Steps = append(result.Steps, step)
+			s = s[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid %q JSONPath expression: unexpected %q", expr, s)
+		}
+	}
+
+	return result, nil
+}
+
+// cutJSONPathName returns the name at the start of s and the rest.
+func cutJSONPathName(s string) (name, rest string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// findJSONPathBracket returns the index of the bracket closing the bracket at
+// the start of s outside of quotes and parentheses.
+func findJSONPathBracket(s string) int {
+	var quote byte
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ']' && depth == 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// parseJSONPathBracket parses the content of a bracket step.
+func parseJSONPathBracket(s string) (jsonPathStep, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "*":
+		return jsonPathStep{Kind: jsonPathWildcard}, nil
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		name, err := unquoteJSONPath(s)
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{Kind: jsonPathField, Name: name}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		return parseJSONPathFilter(s[2 : len(s)-1])
+	case strings.Contains(s, ":"):
+		step := jsonPathStep{Kind: jsonPathSlice}
+		start, end, _ := strings.Cut(s, ":")
+		for _, v := range []struct {
+			Text  string
+			Value **int
+		}{{start, &step.Start}, {end, &step.End}} {
+			if v.Text = strings.TrimSpace(v.Text); v.Text == "" {
+				continue
+			}
+			i, err := strconv.Atoi(v.Text)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("invalid %q slice: %w", s, err)
+			}
+			*v.Value = &i
+		}
+		return step, nil
+	default:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return jsonPathStep{}, fmt.Errorf("invalid %q index: %w", s, err)
+		}
+		return jsonPathStep{Kind: jsonPathIndex, Index: i}, nil
+	}
+}
+
+// parseJSONPathFilter parses a filter like `@.outcome=="failure"`. Filters
+// without an operator match items, which contain the path.
+func parseJSONPathFilter(s string) (jsonPathStep, error) {
+	step := jsonPathStep{Kind: jsonPathFilter}
+	path := s
+	for _, op := range []string{"==", "!="} {
+		if left, right, ok := strings.Cut(s, op); ok {
+			path = left
+			step.Op = op
+			right = strings.TrimSpace(right)
+			if strings.HasPrefix(right, "'") || strings.HasPrefix(right, `"`) {
+				v, err := unquoteJSONPath(right)
+				if err != nil {
+					return step, fmt.Errorf("invalid %s filter value: %w", right, err)
+				}
+				step.Value = v
+			} else if err := json.Unmarshal([]byte(right), &step.Value); err != nil {
+				return step, fmt.Errorf("invalid %s filter value: %w", right, err)
+			}
+			break
+		}
+	}
+
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "@") {
+		return step, fmt.Errorf("invalid %q filter: the path must start with @", s)
+	}
+	expr, err := parseJSONPathExpr(path)
+	if err != nil {
+		return step, err
+	}
+	step.Filter = expr.Steps
+	return step, nil
+}
+
+// collectRecursive appends the values of the name in the value and in all
+// nested values to the result.
+func collectRecursive(result []any, v any, name string) []any {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if name == "*" || k == name {
+				result = append(result, v[k])
+			}
+			result = collectRecursive(result, v[k], name)
+		}
+	case []any:
+		for _, item := range v {
+			result = collectRecursive(result, item, name)
+		}
+	}
+	return result
+}
+
+// evalJSONPath applies the steps to the values and returns the results.
+func evalJSONPath(steps []jsonPathStep, values []any) []any {
+	for _, step := range steps {
+		var next []any
+		for _, v := range values {
+			switch step.Kind {
+			case jsonPathField:
+				if obj, ok := v.(map[string]any); ok {
+					if item, ok := obj[step.Name]; ok {
+						next = append(next, item)
+					}
+				}
+			case jsonPathWildcard:
+				switch v := v.(type) {
+				case map[string]any:
+					for _, k := range slices.Sorted(maps.Keys(v)) {
+						next = append(next, v[k])
+					}
+				case []any:
+					next = append(next, v...)
+				}
+			case jsonPathIndex:
+				if items, ok := v.([]any); ok {
+					i := step.Index
+					if i < 0 {
+						i += len(items)
+					}
+					if i >= 0 && i < len(items) {
+						next = append(next, items[i])
+					}
+				}
+			case jsonPathSlice:
+				if items, ok := v.([]any); ok {
+					bound := func(i *int, fallback int) int {
+						if i == nil {
+							return fallback
+						}
+						if *i < 0 {
+							return max(*i+len(items), 0)
+						}
+						return min(*i, len(items))
+					}
+					start, end := bound(step.Start, 0), bound(step.End, len(items))
+					if start < end {
+						next = append(next, items[start:end]...)
+					}
+				}
+			case jsonPathRecursive:
+				next = collectRecursive(next, v, step.Name)
+			case jsonPathFilter:
+				if items, ok := v.([]any); ok {
+					for _, item := range items {
+						if matchJSONPathFilter(step, item) {
+							next = append(next, item)
+						}
+					}
+				}
+			}
+		}
+		values = next
+	}
+	return values
+}
+
+func matchJSONPathFilter(step jsonPathStep, item any) bool {
+	results := evalJSONPath(step.Filter, []any{item})
+	if step.Op == "" {
+		return len(results) > 0
+	}
+	expected := formatJSONPathValue(step.Value)
+	matches := slices.ContainsFunc(results, func(v any) bool {
+		return formatJSONPathValue(v) == expected
+	})
+	return matches == (step.Op == "==")
+}
+
+// formatJSONPathValue formats strings as text and other values as JSON.
+func formatJSONPathValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// Execute writes the template applied to the data. Multiple results of an
+// expression are separated by spaces, missing values are skipped.
+func (t *jsonPathTemplate) Execute(w io.Writer, data any) error {
+	return executeJSONPathNodes(w, t.Nodes, data, data)
+}
+
+func executeJSONPathNodes(w io.Writer, nodes []jsonPathNode, root, current any) error {
+	start := func(expr *jsonPathExpr) []any {
+		if expr.Root {
+			return []any{root}
+		}
+		return []any{current}
+	}
+
+	for _, node := range nodes {
+		switch {
+		case node.Expr != nil:
+			results := evalJSONPath(node.Expr.Steps, start(node.Expr))
+			texts := make([]string, len(results))
+			for i, v := range results {
+				texts[i] = formatJSONPathValue(v)
+			}
+			if _, err := io.WriteString(w, strings.Join(texts, " ")); err != nil {
+				return err
+			}
+		case node.Range != nil:
+			items := evalJSONPath(node.Range.Steps, start(node.Range))
+			if len(items) == 1 {
+				// a range over a single list iterates over its items
+				if list, ok := items[0].([]any); ok {
+					items = list
+				}
+			}
+			for _, item := range items {
+				if err := executeJSONPathNodes(w, node.Body, root, item); err != nil {
+					return err
+				}
+			}
+		default:
+			if _, err := io.WriteString(w, node.Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -4,21 +4,17 @@
 package client
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cheggaaa/pb/v3"
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/pagination"
-	"github.com/olekukonko/tablewriter"
-	"github.com/olekukonko/tablewriter/tw"
 	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,9 +22,6 @@ import (
 
 const maxOffset = 10000
 
-// precision of the overlap detection
-const precision = 100
-
 var defaultListKeyOrder = []string{
 	"ID",
 	"Time",
@@ -53,46 +46,6 @@ func parseTime(timeStr string) (time.Time, error) {
 	return time.Now(), err
 }
 
-func getTimeListOpts(allEvents *[]events.Event, listOpts *events.ListOpts) error {
-	// time of the last event
-	t := (*allEvents)[len(*allEvents)-1]
-	rt, err := parseTime(t.EventTime)
-	if err != nil {
-		return fmt.Errorf("failed to parse time of the last %s event: %w", t.ID, err)
-	}
-
-	var filter events.DateFilter
-	if getTimeSort(*listOpts) {
-		filter = events.DateFilterLTE
-	} else {
-		filter = events.DateFilterGTE
-	}
-
-	dateFilter := events.DateQuery{
-		Date:   rt,
-		Filter: filter,
-	}
-
-	if len(listOpts.Time) > 0 {
-		var found bool
-		for i, v := range listOpts.Time {
-			if v.Filter == filter {
-				found = true
-				listOpts.Time[i].Date = rt
-			}
-		}
-		if !found {
-			listOpts.Time = append(listOpts.Time, dateFilter)
-		}
-	} else {
-		listOpts.Time = []events.DateQuery{
-			dateFilter,
-		}
-	}
-
-	return nil
-}
-
 func getNextOffset(page pagination.Page) (int, error) {
 	// detect next URL offset
 	next, err := page.NextPageURL()
@@ -113,122 +66,6 @@ func getNextOffset(page pagination.Page) (int, error) {
 	return 0, nil
 }
 
-func getTimeSort(listOpts events.ListOpts) bool {
-	for v := range strings.SplitSeq(listOpts.Sort, ",") {
-		s := strings.SplitN(v, ":", 2)
-		if len(s) == 2 && s[0] == "time" {
-			if s[1] == "asc" {
-				return false
-			}
-			if s[1] == "desc" {
-				return true
-			}
-			return false
-		}
-		if s[0] == "time" {
-			return false
-		}
-	}
-	return true
-}
-
-func getEvents(ctx context.Context, client *gophercloud.ServiceClient, allEvents *[]events.Event, listOpts events.ListOpts, userLimit int, precise bool, bar **pb.ProgressBar) error {
-	var forceWorkaround bool
-	var eventLength int
-
-	err := events.List(client, listOpts).EachPage(ctx, func(ctx context.Context, page pagination.Page) (bool, error) {
-		pageEvents, err := events.ExtractEvents(page)
-		if err != nil {
-			return false, fmt.Errorf("failed to extract events: %w", err)
-		}
-
-		if precise {
-			// add only unique events
-			// detect duplicates of only previous 100 last and further 100 first items
-			// otherwise it is very slow for an amount of objects > 10000 (10000^2*pageN iterations)
-			eventLength = len(*allEvents)
-		ROOTLOOP:
-			for i, evt := range pageEvents {
-				for k, j := 0, eventLength-1; j >= eventLength-precision && j >= 0; k, j = k+1, j-1 {
-					if k >= precision {
-						// don't compare items above 100, break the loop
-						break
-					}
-					if (*allEvents)[j].ID == evt.ID {
-						continue ROOTLOOP
-					}
-				}
-				if i >= precision {
-					// append all remaining and exit the loop
-					*allEvents = append(*allEvents, pageEvents[i:]...)
-					break
-				} else {
-					*allEvents = append(*allEvents, evt)
-				}
-			}
-		} else {
-			*allEvents = append(*allEvents, pageEvents...)
-		}
-
-		eventLength = len(*allEvents)
-
-		if *bar == nil {
-			if v, err := page.(events.EventPage).Total(); err != nil {
-				return false, fmt.Errorf("failed to extract total: %w", err)
-			} else if eventLength <= maxOffset && eventLength != userLimit {
-				if userLimit >= maxOffset && v > userLimit {
-					*bar = pb.New(userLimit)
-				} else if v > maxOffset {
-					*bar = pb.New(v)
-				}
-				if *bar != nil {
-					(*bar).SetWriter(os.Stderr)
-					(*bar).Start()
-				}
-			}
-		}
-
-		if *bar != nil {
-			(*bar).SetCurrent(int64(eventLength))
-		}
-
-		if userLimit > 0 && eventLength >= userLimit {
-			// break the loop, when output userLimit is reached
-			return false, nil
-		}
-
-		nextOffset, err := getNextOffset(page)
-		if err != nil {
-			return false, err
-		}
-
-		if (userLimit == 0 || userLimit > maxOffset) && nextOffset >= maxOffset {
-			// go to the workaround to avoid the 500 http code
-			forceWorkaround = true
-			return false, nil
-		}
-
-		return true, nil
-	})
-	if err != nil {
-		return fmt.Errorf("failed to list events: %w", err)
-	}
-
-	if forceWorkaround && eventLength > 0 {
-		// workaround to avoid 10000 limit 500 code
-		if err = getTimeListOpts(allEvents, &listOpts); err != nil {
-			return err
-		}
-		delta := userLimit - eventLength
-		if delta > 0 && delta <= maxOffset {
-			listOpts.Limit = delta
-		}
-		return getEvents(ctx, client, allEvents, listOpts, userLimit, precise, bar)
-	}
-
-	return nil
-}
-
 // ListCmd represents the list command
 var ListCmd = &cobra.Command{
 	Use:   "list",
@@ -239,12 +76,16 @@ var ListCmd = &cobra.Command{
 			return err
 		}
 
-		// check time flag
-		teq := viper.GetString("time")
-		tgt := viper.GetString("time-start")
-		tlt := viper.GetString("time-end")
-		if teq != "" && (tgt != "" || tlt != "") {
-			return errors.New("cannot combine time flag with time-start or time-end flags")
+		if err := verifyFilterFlags(); err != nil {
+			return err
+		}
+
+		if viper.GetString("from-file") != "" {
+			for _, flag := range fromFileIncompatibleFlags {
+				if viper.GetBool(flag) {
+					return fmt.Errorf("cannot combine from-file flag with %s flag", flag)
+				}
+			}
 		}
 
 		return verifyGlobalFlags(defaultListKeyOrder)
@@ -259,62 +100,19 @@ var ListCmd = &cobra.Command{
 		}
 		format := viper.GetString("format")
 
-		projectID := viper.GetString("project-id")
-		if viper.GetBool("all-projects") {
-			projectID = "*"
+		listOpts, err := buildListOpts(time.Now())
+		if err != nil {
+			return err
 		}
 
-		listOpts := events.ListOpts{
-			Limit:         maxOffset,
-			TargetType:    viper.GetString("target-type"),
-			TargetID:      viper.GetString("target-id"),
-			InitiatorID:   viper.GetString("initiator-id"),
-			InitiatorName: viper.GetString("initiator-name"),
-			Action:        viper.GetString("action"),
-			Outcome:       viper.GetString("outcome"),
-			RequestPath:   viper.GetString("request-path"),
-			ObserverType:  viper.GetString("source"),
-			Search:        viper.GetString("search"),
-			ProjectID:     projectID,
-			Sort:          strings.Join(viper.GetStringSlice("sort"), ","),
-		}
+		applyUserLimit(&listOpts, userLimit)
 
-		// handle user limits <= 10000
-		if userLimit > 0 && userLimit <= maxOffset {
-			// default per page limit
-			listOpts.Limit = userLimit
+		if location := viper.GetString("from-file"); location != "" {
+			return listFromFile(cmd.Context(), location, listOpts, userLimit, format, keyOrder)
 		}
 
-		if t := viper.GetString("time"); t != "" {
-			rt, err := parseTime(t)
-			if err != nil {
-				return fmt.Errorf("failed to parse time: %w", err)
-			}
-			listOpts.Time = []events.DateQuery{
-				{
-					Date: rt,
-				},
-			}
-		}
-		if t := viper.GetString("time-start"); t != "" {
-			rt, err := parseTime(t)
-			if err != nil {
-				return fmt.Errorf("failed to parse time-start: %w", err)
-			}
-			listOpts.Time = append(listOpts.Time, events.DateQuery{
-				Date:   rt,
-				Filter: events.DateFilterGTE,
-			})
-		}
-		if t := viper.GetString("time-end"); t != "" {
-			rt, err := parseTime(t)
-			if err != nil {
-				return fmt.Errorf("failed to parse time-end: %w", err)
-			}
-			listOpts.Time = append(listOpts.Time, events.DateQuery{
-				Date:   rt,
-				Filter: events.DateFilterLTE,
-			})
+		if viper.GetBool("cache") || viper.GetBool("offline") {
+			return listCached(cmd.Context(), listOpts, userLimit, format, keyOrder)
 		}
 
 		client, err := NewHermesV1Client(cmd.Context())
@@ -322,51 +120,49 @@ var ListCmd = &cobra.Command{
 			return fmt.Errorf("failed to create Hermes client: %w", err)
 		}
 
-		var allEvents []events.Event
-		var bar *pb.ProgressBar
-
-		if err = getEvents(cmd.Context(), client, &allEvents, listOpts, userLimit, viper.GetBool("over-10k-fix"), &bar); err != nil {
-			if bar != nil {
-				bar.Finish()
-			}
-			return fmt.Errorf("failed to list the events: %w", err)
+		w, err := newEventWriter(os.Stdout, format, keyOrder)
+		if err != nil {
+			return err
 		}
+
+		var bar *pb.ProgressBar
+		err = getEvents(cmd.Context(), client, listOpts, userLimit, viper.GetInt("concurrency"), &bar, w.Write)
 		if bar != nil {
 			bar.Finish()
 		}
+		if err != nil {
+			return fmt.Errorf("failed to list the events: %w", err)
+		}
 
-		if format == "table" {
-			var buf bytes.Buffer
-			table := tablewriter.NewTable(&buf,
-				tablewriter.WithHeaderMaxWidth(20),
-				tablewriter.WithRowMaxWidth(20),
-				tablewriter.WithRowAlignment(tw.AlignRight),
-			)
-			table.Header(keyOrder)
-
-			for _, v := range allEvents {
-				kv := eventToKV(v)
-				tableRow := []string{}
-				for _, k := range keyOrder {
-					cellValue := kv[k]
-					tableRow = append(tableRow, cellValue)
-				}
-				if err := table.Append(tableRow); err != nil {
-					return fmt.Errorf("error appending row to table: %w", err)
-				}
-			}
+		return w.Close()
+	},
+}
 
-			if err := table.Render(); err != nil {
-				return fmt.Errorf("error rendering table: %w", err)
-			}
+// listCached prints the events from the local cache. Unless the offline flag
+// is specified, time windows, which are not cached yet, are fetched first.
+func listCached(ctx context.Context, listOpts events.ListOpts, userLimit int, format string, keyOrder []string) error {
+	cache, err := openEventCache()
+	if err != nil {
+		return err
+	}
 
-			fmt.Print(buf.String())
-		} else {
-			return printEvent(allEvents, format, keyOrder)
+	var client *gophercloud.ServiceClient
+	if !viper.GetBool("offline") {
+		client, err = NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
 		}
+	}
 
-		return nil
-	},
+	allEvents, err := cache.listCachedEvents(ctx, client, listOpts, viper.GetInt("concurrency"))
+	if err != nil {
+		return fmt.Errorf("failed to list the cached events: %w", err)
+	}
+	if userLimit > 0 && len(allEvents) > userLimit {
+		allEvents = allEvents[:userLimit]
+	}
+
+	return printEvent(allEvents, format, keyOrder)
 }
 
 func init() {
@@ -375,24 +171,16 @@ func init() {
 }
 
 func initListCmdFlags() {
-	ListCmd.Flags().StringP("target-type", "", "", "filter events by a target type")
-	ListCmd.Flags().StringP("target-id", "", "", "filter events by a target ID")
-	ListCmd.Flags().StringP("initiator-id", "", "", "filter events by an initiator ID")
-	ListCmd.Flags().StringP("initiator-name", "", "", "filter events by an initiator name")
-	ListCmd.Flags().StringP("action", "", "", "filter events by an action")
-	ListCmd.Flags().StringP("outcome", "", "", "filter events by an outcome")
-	ListCmd.Flags().StringP("request-path", "", "", "filter events by a request path")
-	ListCmd.Flags().StringP("source", "", "", "filter events by a source")
-	ListCmd.Flags().StringP("search", "", "", "filter events by a search string")
-	ListCmd.Flags().StringP("time", "", "", "filter events by time")
-	ListCmd.Flags().StringP("time-start", "", "", "filter events from time")
-	ListCmd.Flags().StringP("time-end", "", "", "filter events till time")
-	ListCmd.Flags().StringP("project-id", "", "", "filter events by the project or domain ID (admin only)")
-	ListCmd.Flags().BoolP("all-projects", "A", false, "include all projects and domains (admin only) (alias for --project-id '*')")
+	addFilterFlags(ListCmd.Flags())
+	addTimeFilterFlags(ListCmd.Flags())
+	addSortFlag(ListCmd.Flags())
 	ListCmd.Flags().BoolP("over-10k-fix", "", true, "workaround to filter out overlapping events for > 10k total events")
+	ListCmd.Flags().MarkDeprecated("over-10k-fix", "events are always deduplicated by their ID") //nolint:errcheck
+	ListCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
 	ListCmd.Flags().UintP("limit", "l", 0, "limit an amount of events in output")
-	ListCmd.Flags().StringSliceP("sort", "s", []string{}, `supported sort keys include time, observer_type, target_type, target_id, initiator_type, initiator_id, outcome and action
-each sort key may also include a direction suffix
-supported directions are ":asc" for ascending and ":desc" for descending
-can be specified multiple times`)
+	ListCmd.Flags().BoolP("cache", "", false, "store fetched events in the local cache and fetch only time windows, which are not cached yet")
+	ListCmd.Flags().BoolP("offline", "", false, "list events from the local cache only")
+	ListCmd.Flags().StringP("from-file", "", "", "list events from an export file or a swift://container/object export object instead of Hermes")
+	ListCmd.Flags().StringSlice("identity", nil, "age identity file to decrypt an encrypted export file, can be specified multiple times")
+	ListCmd.Flags().String("passphrase-cmd", "", "command, which prints the passphrase to decrypt an encrypted export file")
 }
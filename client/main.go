@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"slices"
@@ -42,11 +43,14 @@ func Execute() {
 func initRootCmdFlags() {
 	// debug flag
 	RootCmd.PersistentFlags().BoolP("debug", "d", false, "print out request and response objects")
-	RootCmd.PersistentFlags().StringSliceP("column", "c", []string{}, "an event column to print")
-	RootCmd.PersistentFlags().StringP("format", "f", "table", "the output format")
-	viper.BindPFlag("debug", RootCmd.PersistentFlags().Lookup("debug"))   //nolint:errcheck
-	viper.BindPFlag("column", RootCmd.PersistentFlags().Lookup("column")) //nolint:errcheck
-	viper.BindPFlag("format", RootCmd.PersistentFlags().Lookup("format")) //nolint:errcheck
+	RootCmd.PersistentFlags().StringSliceP("column", "c", []string{}, "an event column or a field path, e.g. Initiator.Host.Address, to print")
+	RootCmd.PersistentFlags().StringP("format", "f", "table", "the output format, commands printing events also support template=<go-template>, template-file=<path>, template-list=<go-template>, template-list-file=<path> and jsonpath=<template>")
+	RootCmd.PersistentFlags().StringP("timezone", "", "", `time zone for time flags without a time zone and for event times in output, e.g. "Local" or "Europe/Berlin" (default: UTC)`)
+	RootCmd.RegisterFlagCompletionFunc("column", completeColumns)             //nolint:errcheck
+	viper.BindPFlag("debug", RootCmd.PersistentFlags().Lookup("debug"))       //nolint:errcheck
+	viper.BindPFlag("column", RootCmd.PersistentFlags().Lookup("column"))     //nolint:errcheck
+	viper.BindPFlag("format", RootCmd.PersistentFlags().Lookup("format"))     //nolint:errcheck
+	viper.BindPFlag("timezone", RootCmd.PersistentFlags().Lookup("timezone")) //nolint:errcheck
 }
 
 // NewHermesV1Client returns a *ServiceClient for making calls
@@ -101,29 +105,51 @@ func NewHermesV1Client(ctx context.Context) (*gophercloud.ServiceClient, error)
 	})
 }
 
-func verifyGlobalFlags(columnsOrder []string) error {
+// verifyGlobalFlags verifies the column, format and time zone flags. Commands
+// with their own output formats pass the supported formats.
+func verifyGlobalFlags(columnsOrder []string, formats ...string) error {
 	// verify supported columns
 	columns := viper.GetStringSlice("column")
 	for _, c := range columns {
 		if len(columnsOrder) == 0 {
 			return errors.New(`columns are not supported for this command`)
 		}
-		if !slices.Contains(columnsOrder, c) {
-			return fmt.Errorf(`invalid "%s" column name, supported values for the column: %s`, c, strings.Join(columnsOrder, ", "))
+		if slices.Contains(columnsOrder, c) {
+			continue
+		}
+		if err := verifyFieldPath(c); err != nil {
+			return fmt.Errorf(`invalid "%s" column name, supported values for the column: %s or a field path, e.g. Initiator.Host.Address: %w`, c, strings.Join(columnsOrder, ", "), err)
 		}
 	}
 
 	// verify supported formats
-	if !slices.Contains(defaultPrintFormats, viper.GetString("format")) {
-		return fmt.Errorf(`invalid "%s" column name, supported values for the format: %s`, viper.GetString("format"), strings.Join(defaultPrintFormats, ", "))
+	format := viper.GetString("format")
+	supported := formats
+	if len(formats) == 0 {
+		supported = defaultPrintFormats
+		// commands printing events support templates and JSONPath
+		if len(columnsOrder) > 0 {
+			if _, ok, err := newCustomEventWriter(io.Discard, format); err != nil {
+				return err
+			} else if ok {
+				supported = append(slices.Clone(supported), format)
+			}
+		}
+	}
+	if !slices.Contains(supported, format) {
+		if len(formats) == 0 && len(columnsOrder) > 0 {
+			supported = append(slices.Clone(supported), "template=<go-template>", "template-file=<path>", "template-list=<go-template>", "template-list-file=<path>", "jsonpath=<template>")
+		}
+		return fmt.Errorf(`invalid "%s" format, supported values for the format: %s`, format, strings.Join(supported, ", "))
 	}
 
-	// verify the project ID and the domain ID parameters
-	projectID := viper.GetString("project-id")
-	allProjects := viper.GetBool("all-projects")
-	if projectID != "" && allProjects {
-		return errors.New("--project-id and --all-projects cannot be both specified")
+	// verify the time zone
+	var err error
+	timezone, err = parseTimezone(viper.GetString("timezone"))
+	if err != nil {
+		return err
 	}
 
-	return nil
+	// verify the project ID and the domain ID parameters
+	return verifyScopeFlags()
 }
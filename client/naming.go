@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gophercloud/utils/v2/env"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+// defaultNameTemplate is the name template of exports without the filename
+// and the name-template flags
+const defaultNameTemplate = "hermes-export-{now}"
+
+// nameTimeFormat is a safe and sortable time format for object names
+const nameTimeFormat = "2006-01-02-150405"
+
+// maxObjectNameLength is the maximum length of a Swift object name in bytes
+const maxObjectNameLength = 1024
+
+// nameTemplatePlaceholders are the placeholders supported in name templates
+var nameTemplatePlaceholders = []string{"project", "region", "start", "end", "now", "format", "filters_hash"}
+
+type CollisionPolicy string
+
+const (
+	CollisionFail      CollisionPolicy = "fail"
+	CollisionOverwrite CollisionPolicy = "overwrite"
+	CollisionSuffix    CollisionPolicy = "suffix"
+)
+
+var (
+	allCollisionPolicies = []CollisionPolicy{
+		CollisionFail,
+		CollisionOverwrite,
+		CollisionSuffix,
+	}
+)
+
+func parseCollisionPolicy(input string) (CollisionPolicy, error) {
+	if slices.Contains(allCollisionPolicies, CollisionPolicy(input)) {
+		return CollisionPolicy(input), nil
+	}
+	return "", fmt.Errorf("unsupported collision policy: %s (supported policies: %v)", input, allCollisionPolicies)
+}
+
+// verifyExportName verifies that the name of an export file is usable as a
+// Swift object name and as a relative local path. The format, compression
+// and encryption extensions are appended to the name.
+func verifyExportName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("export name cannot be empty")
+	case !utf8.ValidString(name):
+		return fmt.Errorf("export name %q is not valid UTF-8", name)
+	case strings.ContainsFunc(name, unicode.IsControl):
+		return fmt.Errorf("export name %q cannot contain control characters", name)
+	case strings.HasPrefix(name, "/"):
+		return fmt.Errorf("export name %q cannot start with /", name)
+	case strings.Contains(name, segmentsSuffix):
+		return fmt.Errorf("export name %q cannot contain %q, which is reserved for segments", name, segmentsSuffix)
+	}
+	for elem := range strings.SplitSeq(name, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("export name %q contains an invalid path element %q", name, elem)
+		}
+	}
+
+	// leave space for the longest extension and the segment names
+	if n := len(name) + len(".yaml.zst.age.manifest.json.sig") + len(segmentsSuffix) + 20; n > maxObjectNameLength {
+		return fmt.Errorf("export name %q is too long for Swift object names", name)
+	}
+	return nil
+}
+
+// verifyNameTemplate verifies that the name template contains only supported
+// placeholders and no invalid path elements.
+func verifyNameTemplate(template string) error {
+	if template == "" {
+		return errors.New("name template cannot be empty")
+	}
+	for _, m := range partitionPlaceholderRx.FindAllStringSubmatch(template, -1) {
+		if !slices.Contains(nameTemplatePlaceholders, m[1]) {
+			return fmt.Errorf("name template %q contains an unknown {%s} placeholder (supported placeholders: %v)", template, m[1], nameTemplatePlaceholders)
+		}
+	}
+	// placeholders are never expanded to empty or unsafe values
+	return verifyExportName(partitionPlaceholderRx.ReplaceAllString(template, "x"))
+}
+
+// filtersHash returns a short hash of the filters of the list options. Time
+// filters, the sort order and the paging are not part of the hash, so
+// exports of different time ranges with the same filters get the same hash.
+func filtersHash(listOpts events.ListOpts) (string, error) {
+	listOpts.Time = nil
+	listOpts.Sort = ""
+	listOpts.Limit = 0
+	listOpts.Offset = 0
+	data, err := json.Marshal(listOpts)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// nameTemplateValues returns the values of the name template placeholders.
+// Times are formatted in the timezone, the local time zone is used when no
+// timezone is set.
+func nameTemplateValues(listOpts events.ListOpts, format ExportFormat, now time.Time) (map[string]string, error) {
+	formatTime := func(t time.Time) string {
+		if timezone != nil {
+			return t.In(timezone).Format(nameTimeFormat)
+		}
+		return t.Local().Format(nameTimeFormat)
+	}
+
+	values := map[string]string{
+		"region": env.Getenv("OS_REGION_NAME"),
+		"now":    formatTime(now),
+		"end":    formatTime(now),
+		"format": string(format),
+	}
+
+	switch scope := cmp.Or(listOpts.ProjectID, listOpts.DomainID); scope {
+	case "*":
+		values["project"] = "all"
+	case "":
+		values["project"] = cmp.Or(env.Getenv("OS_PROJECT_ID"), env.Getenv("OS_PROJECT_NAME"))
+	default:
+		values["project"] = scope
+	}
+
+	for _, v := range listOpts.Time {
+		switch v.Filter {
+		case events.DateFilterGT, events.DateFilterGTE:
+			values["start"] = formatTime(v.Date)
+		case events.DateFilterLT, events.DateFilterLTE:
+			values["end"] = formatTime(v.Date)
+		default:
+			values["start"] = formatTime(v.Date)
+			values["end"] = formatTime(v.Date)
+		}
+	}
+
+	hash, err := filtersHash(listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash filters: %w", err)
+	}
+	values["filters_hash"] = hash
+
+	return values, nil
+}
+
+// exportNameCollides returns true, when the destination contains an export
+// file, a partitioned export or segments with the name.
+func exportNameCollides(ctx context.Context, destination exportDestination, name string) (bool, error) {
+	objects, err := destination.List(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	for _, o := range objects {
+		rest := strings.TrimPrefix(o.Name, name)
+		if strings.HasPrefix(rest, "/") || strings.HasPrefix(rest, segmentsSuffix) || slices.Contains(exportFileExtensions(), rest) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolveExportName applies the collision policy to the export name. The
+// suffix policy appends the first free number, e.g. "-2".
+func resolveExportName(ctx context.Context, destination exportDestination, name string, policy CollisionPolicy) (string, error) {
+	if policy == CollisionOverwrite {
+		return name, nil
+	}
+
+	collides, err := exportNameCollides(ctx, destination, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to check the export name: %w", err)
+	}
+	if !collides {
+		return name, nil
+	}
+	if policy == CollisionFail {
+		return "", fmt.Errorf("an export named %s already exists, use --on-collision overwrite or suffix", name)
+	}
+
+	for i := 2; i < 1000; i++ {
+		candidate := name + "-" + strconv.Itoa(i)
+		collides, err := exportNameCollides(ctx, destination, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check the export name: %w", err)
+		}
+		if !collides {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find a free suffix for the export name %s", name)
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestVerifyNameTemplate(t *testing.T) {
+	for _, template := range []string{
+		defaultNameTemplate,
+		"{project}/{region}/{start}_{end}.{filters_hash}",
+		"audit/{format}/export-{now}",
+	} {
+		if err := verifyNameTemplate(template); err != nil {
+			t.Errorf("expected %q to be valid: %s", template, err)
+		}
+	}
+
+	for _, template := range []string{
+		"",
+		"{unknown}",
+		"/{project}",
+		"{project}//{now}",
+		"../{project}",
+		"{project}-segments/{now}",
+		"export\n{now}",
+		strings.Repeat("x", maxObjectNameLength),
+	} {
+		if err := verifyNameTemplate(template); err == nil {
+			t.Errorf("expected %q to fail", template)
+		}
+	}
+}
+
+func TestNameTemplateValues(t *testing.T) {
+	t.Setenv("OS_REGION_NAME", "eu-de-1")
+	t.Setenv("OS_PROJECT_ID", "")
+	t.Setenv("OS_PROJECT_NAME", "audit team")
+	timezone = time.UTC
+	defer func() { timezone = nil }()
+
+	listOpts := events.ListOpts{
+		Action: "delete",
+		Time: []events.DateQuery{
+			{Date: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Filter: events.DateFilterGTE},
+		},
+	}
+	now := time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)
+	values, err := nameTemplateValues(listOpts, ExportFormatCSV, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := expandPartitionTemplate("{project}/{region}/{start}_{end}-{format}-{filters_hash}", values)
+
+	// the hash does not depend on the time range
+	listOpts.Time = nil
+	hash, err := filtersHash(listOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "audit_team/eu-de-1/2024-05-01-000000_2024-05-02-103000-csv-" + hash
+	if result != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+}
+
+func TestResolveExportName(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	for _, name := range []string{"audit.json.gz", "audit-2.csv", "partitioned/2024/05/01.json", "hermes-export.state.json"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("[]"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	destination := fileDestination{Dir: dir}
+
+	cases := []struct {
+		Name     string
+		Policy   CollisionPolicy
+		Expected string
+	}{
+		{"new", CollisionFail, "new"},
+		{"hermes-export", CollisionFail, "hermes-export"},
+		{"audit", CollisionOverwrite, "audit"},
+		{"audit", CollisionSuffix, "audit-3"},
+		{"partitioned", CollisionSuffix, "partitioned-2"},
+	}
+	for _, c := range cases {
+		result, err := resolveExportName(ctx, destination, c.Name, c.Policy)
+		if err != nil {
+			t.Errorf("failed to resolve %q with the %s policy: %s", c.Name, c.Policy, err)
+			continue
+		}
+		if result != c.Expected {
+			t.Errorf("expected %q with the %s policy to be resolved to %q, got %q", c.Name, c.Policy, c.Expected, result)
+		}
+	}
+
+	for _, name := range []string{"audit", "partitioned"} {
+		if _, err := resolveExportName(ctx, destination, name, CollisionFail); err == nil {
+			t.Errorf("expected %q to collide", name)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+type PartitionKey string
+
+const (
+	PartitionDay     PartitionKey = "day"
+	PartitionHour    PartitionKey = "hour"
+	PartitionProject PartitionKey = "project"
+	PartitionSource  PartitionKey = "source"
+)
+
+var (
+	allPartitionKeys = []PartitionKey{
+		PartitionDay,
+		PartitionHour,
+		PartitionProject,
+		PartitionSource,
+	}
+)
+
+// partitionPlaceholders maps the path template placeholders to the partition
+// keys, which provide them
+var partitionPlaceholders = map[string][]PartitionKey{
+	"project": {PartitionProject},
+	"source":  {PartitionSource},
+	"yyyy":    {PartitionDay, PartitionHour},
+	"mm":      {PartitionDay, PartitionHour},
+	"dd":      {PartitionDay, PartitionHour},
+	"hh":      {PartitionHour},
+}
+
+// partitionRequiredPlaceholders are the placeholders, which must be used in
+// the path template to keep the partitions apart
+var partitionRequiredPlaceholders = map[PartitionKey][]string{
+	PartitionDay:     {"yyyy", "mm", "dd"},
+	PartitionHour:    {"yyyy", "mm", "dd", "hh"},
+	PartitionProject: {"project"},
+	PartitionSource:  {"source"},
+}
+
+var partitionPlaceholderRx = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// unsafePathCharsRx matches characters, which are replaced in partition
+// values to keep them usable as a single path element
+var unsafePathCharsRx = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// parsePartitionKeys parses a comma separated list of partition keys
+func parsePartitionKeys(input string) ([]PartitionKey, error) {
+	var keys []PartitionKey
+	for v := range strings.SplitSeq(input, ",") {
+		key := PartitionKey(strings.TrimSpace(v))
+		if !slices.Contains(allPartitionKeys, key) {
+			return nil, fmt.Errorf("unsupported partition: %s (supported partitions: %v)", key, allPartitionKeys)
+		}
+		if slices.Contains(keys, key) {
+			return nil, fmt.Errorf("duplicate partition: %s", key)
+		}
+		keys = append(keys, key)
+	}
+	if slices.Contains(keys, PartitionDay) && slices.Contains(keys, PartitionHour) {
+		return nil, fmt.Errorf("cannot combine %s and %s partitions", PartitionDay, PartitionHour)
+	}
+	return keys, nil
+}
+
+// defaultPartitionTemplate returns the path template, which contains the
+// partition keys in the given order, e.g. "{project}/{yyyy}/{mm}/{dd}"
+func defaultPartitionTemplate(keys []PartitionKey) string {
+	var elems []string
+	for _, key := range keys {
+		for _, p := range partitionRequiredPlaceholders[key] {
+			elems = append(elems, "{"+p+"}")
+		}
+	}
+	return strings.Join(elems, "/")
+}
+
+// verifyPartitionTemplate verifies that the template uses all partition keys
+// and only the placeholders provided by them.
+func verifyPartitionTemplate(template string, keys []PartitionKey) error {
+	if template == "" {
+		return errors.New("partition template cannot be empty")
+	}
+	if strings.HasPrefix(template, "/") {
+		return fmt.Errorf("partition template %q cannot start with /", template)
+	}
+	for elem := range strings.SplitSeq(template, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return fmt.Errorf("partition template %q contains an invalid path element %q", template, elem)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, m := range partitionPlaceholderRx.FindAllStringSubmatch(template, -1) {
+		providers, ok := partitionPlaceholders[m[1]]
+		if !ok {
+			return fmt.Errorf("partition template %q contains an unknown {%s} placeholder", template, m[1])
+		}
+		if !slices.ContainsFunc(providers, func(key PartitionKey) bool { return slices.Contains(keys, key) }) {
+			return fmt.Errorf("partition template %q contains the {%s} placeholder, which requires the %v partition", template, m[1], providers)
+		}
+		used[m[1]] = true
+	}
+
+	for _, key := range keys {
+		for _, p := range partitionRequiredPlaceholders[key] {
+			if !used[p] {
+				return fmt.Errorf("partition template %q must contain the {%s} placeholder of the %s partition", template, p, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// eventProject returns the project or domain, which owns the event
+func eventProject(evt events.Event) string {
+	for _, id := range []string{evt.Target.ProjectID, evt.Initiator.ProjectID, evt.Target.DomainID, evt.Initiator.DomainID} {
+		if id != "" {
+			return id
+		}
+	}
+	return "unknown"
+}
+
+// partitionValues returns the placeholder values of the event
+func partitionValues(evt events.Event, keys []PartitionKey) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, key := range keys {
+		switch key {
+		case PartitionProject:
+			values["project"] = eventProject(evt)
+		case PartitionSource:
+			values["source"] = evt.Observer.TypeURI
+			if values["source"] == "" {
+				values["source"] = "unknown"
+			}
+		case PartitionDay, PartitionHour:
+			t, err := parseTime(evt.EventTime)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse time of the %s event: %w", evt.ID, err)
+			}
+			if timezone != nil {
+				t = t.In(timezone)
+			}
+			values["yyyy"] = t.Format("2006")
+			values["mm"] = t.Format("01")
+			values["dd"] = t.Format("02")
+			values["hh"] = t.Format("15")
+		}
+	}
+	return values, nil
+}
+
+// expandPartitionTemplate replaces the placeholders in the template
+func expandPartitionTemplate(template string, values map[string]string) string {
+	return partitionPlaceholderRx.ReplaceAllStringFunc(template, func(m string) string {
+		v := strings.Trim(unsafePathCharsRx.ReplaceAllString(values[m[1:len(m)-1]], "_"), ".")
+		if v == "" {
+			return "unknown"
+		}
+		return v
+	})
+}
+
+// escapeReservedPartitionPath appends an underscore to the path elements of
+// the partition, which would be mistaken for the partition manifest, an
+// integrity manifest, the incremental export state or a segments prefix.
+func escapeReservedPartitionPath(p string) string {
+	elems := strings.Split(p, "/")
+	for i, elem := range elems {
+		reserved := strings.HasSuffix(elem, strings.TrimSuffix(segmentsSuffix, "/"))
+		if i == len(elems)-1 {
+			reserved = reserved || elem == "manifest" ||
+				strings.HasSuffix(elem, integrityManifestSuffix) ||
+				elem == strings.TrimSuffix(defaultExportStateName, "."+string(ExportFormatJSON))
+		}
+		if reserved {
+			elems[i] = elem + "_"
+		}
+	}
+	return strings.Join(elems, "/")
+}
+
+// exportPartition is a part of the exported events, which is written into a
+// separate file
+type exportPartition struct {
+	Path   string
+	Events []events.Event
+}
+
+// partitionEvents splits the events into partitions, which are sorted by
+// their path. The order of the events is kept within the partitions.
+func partitionEvents(allEvents []events.Event, keys []PartitionKey, template string) ([]exportPartition, error) {
+	byPath := make(map[string]*exportPartition)
+	for _, evt := range allEvents {
+		values, err := partitionValues(evt, keys)
+		if err != nil {
+			return nil, err
+		}
+		p := escapeReservedPartitionPath(expandPartitionTemplate(template, values))
+		if byPath[p] == nil {
+			byPath[p] = &exportPartition{Path: p}
+		}
+		byPath[p].Events = append(byPath[p].Events, evt)
+	}
+
+	result := make([]exportPartition, 0, len(byPath))
+	for _, p := range byPath {
+		result = append(result, *p)
+	}
+	slices.SortFunc(result, func(a, b exportPartition) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return result, nil
+}
+
+// exportManifest lists the files of a partitioned export
+type exportManifest struct {
+	CreatedAt   time.Time                 `json:"created_at"`
+	PartitionBy []PartitionKey            `json:"partition_by"`
+	Template    string                    `json:"template"`
+	Format      ExportFormat              `json:"format"`
+	Compression Compression               `json:"compression,omitempty"`
+	Events      int                       `json:"events"`
+	Partitions  []exportManifestPartition `json:"partitions"`
+}
+
+type exportManifestPartition struct {
+	Path           string    `json:"path"`
+	Events         int       `json:"events"`
+	FirstEventTime time.Time `json:"first_event_time"`
+	LastEventTime  time.Time `json:"last_event_time"`
+}
+
+// newExportManifestPartition describes the exported partition
+func newExportManifestPartition(path string, partitionEvents []events.Event) exportManifestPartition {
+	result := exportManifestPartition{
+		Path:   path,
+		Events: len(partitionEvents),
+	}
+	for _, evt := range partitionEvents {
+		t, err := parseTime(evt.EventTime)
+		if err != nil {
+			continue
+		}
+		if result.FirstEventTime.IsZero() || t.Before(result.FirstEventTime) {
+			result.FirstEventTime = t
+		}
+		if t.After(result.LastEventTime) {
+			result.LastEventTime = t
+		}
+	}
+	return result
+}
+
+// exportExtensionRx matches the file name extensions, which are derived from
+// the export format, compression and encryption
+var exportExtensionRx = regexp.MustCompile(`(\.(json|csv|yaml))?(\.(gz|zst))?(\.age)?$`)
+
+// getPartitioning parses the partition flags. The file name extension is
+// removed from the template, because it is derived from the export format
+// and compression.
+func getPartitioning(partitionBy, template string) ([]PartitionKey, string, error) {
+	keys, err := parsePartitionKeys(partitionBy)
+	if err != nil {
+		return nil, "", err
+	}
+	if template == "" {
+		template = defaultPartitionTemplate(keys)
+	}
+	template = exportExtensionRx.ReplaceAllString(template, "")
+	if err := verifyPartitionTemplate(template, keys); err != nil {
+		return nil, "", err
+	}
+	return keys, template, nil
+}
+
+// uploadExportManifest writes the manifest next to the partitions
+func uploadExportManifest(ctx context.Context, destination exportDestination, manifest exportManifest, fileName string, segmentSize uint64) (ExportFile, error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return ExportFile{}, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	f := ExportFile{
+		Format:      ExportFormatJSON,
+		FileName:    fileName + "/manifest",
+		SegmentSize: segmentSize,
+		Contents:    bytes.NewReader(data),
+	}
+	if err := destination.Upload(ctx, f); err != nil {
+		return f, fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestGetPartitioning(t *testing.T) {
+	cases := []struct {
+		PartitionBy string
+		Template    string
+		Expected    string
+	}{
+		{"day", "", "{yyyy}/{mm}/{dd}"},
+		{"project,hour", "", "{project}/{yyyy}/{mm}/{dd}/{hh}"},
+		{"project,day", "{project}/{yyyy}/{mm}/{dd}.json", "{project}/{yyyy}/{mm}/{dd}"},
+		{"source,day", "{source}/{yyyy}-{mm}-{dd}.csv.gz", "{source}/{yyyy}-{mm}-{dd}"},
+	}
+	for _, c := range cases {
+		_, template, err := getPartitioning(c.PartitionBy, c.Template)
+		if err != nil {
+			t.Errorf("failed to parse %q partitioning: %s", c.PartitionBy, err)
+			continue
+		}
+		if template != c.Expected {
+			t.Errorf("expected %q template, got %q", c.Expected, template)
+		}
+	}
+
+	failures := []struct {
+		PartitionBy string
+		Template    string
+	}{
+		{"week", ""},
+		{"day,hour", ""},
+		{"day,day", ""},
+		{"day", "{yyyy}/{mm}"},
+		{"day", "{project}/{yyyy}/{mm}/{dd}"},
+		{"day", "{yyyy}/{mm}/{dd}/{hh}"},
+		{"project", "../{project}"},
+		{"project", "/{project}"},
+		{"project", "{project}/{foo}"},
+	}
+	for _, c := range failures {
+		if _, _, err := getPartitioning(c.PartitionBy, c.Template); err == nil {
+			t.Errorf("expected %q partitioning with %q template to fail", c.PartitionBy, c.Template)
+		}
+	}
+}
+
+func TestPartitionEvents(t *testing.T) {
+	allEvents := []events.Event{
+		{ID: "1", EventTime: "2024-05-01T10:00:00+0000", Target: cadf.Resource{ProjectID: "p2"}},
+		{ID: "2", EventTime: "2024-05-01T23:59:59+0000", Initiator: cadf.Resource{ProjectID: "p1"}},
+		{ID: "3", EventTime: "2024-05-02T00:00:00+0000", Target: cadf.Resource{ProjectID: "p2"}},
+		{ID: "4", EventTime: "2024-05-01T11:00:00+0000", Target: cadf.Resource{ProjectID: "p2"}},
+		{ID: "5", EventTime: "2024-05-01T11:00:00+0000", Target: cadf.Resource{ProjectID: "../x"}},
+	}
+
+	partitions, err := partitionEvents(allEvents, []PartitionKey{PartitionProject, PartitionDay}, "{project}/{yyyy}/{mm}/{dd}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"_x/2024/05/01": {"5"},
+		"p1/2024/05/01": {"2"},
+		"p2/2024/05/01": {"1", "4"},
+		"p2/2024/05/02": {"3"},
+	}
+	if len(partitions) != len(expected) {
+		t.Fatalf("expected %d partitions, got %d", len(expected), len(partitions))
+	}
+	for _, p := range partitions {
+		var ids []string
+		for _, evt := range p.Events {
+			ids = append(ids, evt.ID)
+		}
+		if !slices.Equal(expected[p.Path], ids) {
+			t.Errorf("unexpected events %v in %q partition", ids, p.Path)
+		}
+	}
+	if partitions[0].Path != "_x/2024/05/01" {
+		t.Errorf("expected partitions to be sorted by path, got %q first", partitions[0].Path)
+	}
+
+	// partition values must not collide with the manifests, the state and
+	// the segments
+	cases := map[string]string{
+		"manifest":            "manifest_",
+		"p1.manifest":         "p1.manifest_",
+		"hermes-export.state": "hermes-export.state_",
+		"p1-segments":         "p1-segments_",
+		"manifests":           "manifests",
+	}
+	for project, expected := range cases {
+		evt := events.Event{ID: "1", EventTime: "2024-05-01T10:00:00+0000", Target: cadf.Resource{ProjectID: project}}
+		partitions, err := partitionEvents([]events.Event{evt}, []PartitionKey{PartitionProject}, "{project}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if partitions[0].Path != expected {
+			t.Errorf("expected the %q project to be written to %q, got %q", project, expected, partitions[0].Path)
+		}
+	}
+	partitions, err = partitionEvents(allEvents[:1], []PartitionKey{PartitionProject}, "{project}-segments/data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partitions[0].Path != "p2-segments_/data" {
+		t.Errorf("expected the segments prefix to be escaped, got %q", partitions[0].Path)
+	}
+}
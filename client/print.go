@@ -9,8 +9,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
 	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
 	"gopkg.in/yaml.v3"
 )
@@ -21,13 +24,14 @@ var defaultPrintFormats = []string{
 	"json",
 	"csv",
 	"yaml",
+	"ndjson",
 }
 
 func eventToKV(event events.Event) map[string]string {
 	kv := make(map[string]string)
 	kv["ID"] = event.ID
 	kv["Type"] = event.EventType
-	kv["Time"] = event.EventTime
+	kv["Time"] = formatEventTime(event.EventTime)
 
 	if event.Observer.Name != "" {
 		kv["Observer"] = event.Observer.Name
@@ -55,6 +59,13 @@ func eventToKV(event events.Event) map[string]string {
 		kv["InitiatorAppCredential"] = event.Initiator.AppCredentialID
 	}
 
+	if event.Initiator.RequestID != "" {
+		kv["RequestID"] = event.Initiator.RequestID
+	}
+	if event.Initiator.GlobalRequestID != "" {
+		kv["GlobalRequestID"] = event.Initiator.GlobalRequestID
+	}
+
 	if event.RequestPath != "" {
 		kv["RequestPath"] = event.RequestPath
 	}
@@ -77,93 +88,300 @@ func eventToKV(event events.Event) map[string]string {
 	return kv
 }
 
-func printEvent(allEvents []events.Event, format string, keyOrder []string) error {
+// eventWriter writes events in a specific output format. Events are written
+// as soon as they are passed to Write, except for the table format, which
+// requires all rows to be known before rendering.
+type eventWriter interface {
+	Write(allEvents []events.Event) error
+	// Close writes the remaining output
+	Close() error
+}
+
+func newEventWriter(w io.Writer, format string, keyOrder []string) (eventWriter, error) {
 	switch format {
+	case "table":
+		return newTableEventWriter(w, keyOrder), nil
 	case "json":
-		return printJSON(allEvents)
+		return &jsonEventWriter{w: w}, nil
+	case "ndjson":
+		return &ndjsonEventWriter{w: w}, nil
 	case "yaml":
-		return printYAML(allEvents)
+		return &yamlEventWriter{w: w}, nil
 	case "csv":
-		return printCSV(allEvents, keyOrder)
+		return &csvEventWriter{w: csv.NewWriter(w), keyOrder: keyOrder}, nil
 	case "value":
-		return printValue(allEvents, keyOrder)
+		return &valueEventWriter{w: w, keyOrder: keyOrder}, nil
+	}
+	if ew, ok, err := newCustomEventWriter(w, format); ok {
+		return ew, err
 	}
-	return fmt.Errorf("unsupported format: %s", format)
+	return nil, fmt.Errorf("unsupported format: %s", format)
 }
 
-func printCSV(allEvents []events.Event, keyOrder []string) error {
-	var buf bytes.Buffer
-	csvWriter := csv.NewWriter(&buf)
+func printEvent(allEvents []events.Event, format string, keyOrder []string) error {
+	w, err := newEventWriter(os.Stdout, format, keyOrder)
+	if err != nil {
+		return err
+	}
+	if err := w.Write(allEvents); err != nil {
+		return err
+	}
+	return w.Close()
+}
 
-	if err := csvWriter.Write(keyOrder); err != nil {
-		return fmt.Errorf("error writing header to csv: %w", err)
+func eventToRow(event events.Event, keyOrder []string) []string {
+	kv := eventToKV(event)
+	row := make([]string, len(keyOrder))
+	for i, k := range keyOrder {
+		row[i], _ = eventColumnValue(event, kv, k)
 	}
+	return row
+}
+
+type tableEventWriter struct {
+	w     io.Writer
+	buf   bytes.Buffer
+	table *tablewriter.Table
+
+	keyOrder []string
+}
 
+func newTableEventWriter(w io.Writer, keyOrder []string) *tableEventWriter {
+	t := &tableEventWriter{w: w, keyOrder: keyOrder}
+	t.table = tablewriter.NewTable(&t.buf,
+		tablewriter.WithHeaderMaxWidth(20),
+		tablewriter.WithRowMaxWidth(20),
+		tablewriter.WithRowAlignment(tw.AlignRight),
+	)
+	t.table.Header(keyOrder)
+	return t
+}
+
+func (t *tableEventWriter) Write(allEvents []events.Event) error {
 	for _, v := range allEvents {
-		kv := eventToKV(v)
-		tableRow := []string{}
-		for _, k := range keyOrder {
-			v := kv[k]
-			tableRow = append(tableRow, v)
-		}
-		if err := csvWriter.Write(tableRow); err != nil {
-			return fmt.Errorf("error writing record to csv: %w", err)
+		if err := t.table.Append(eventToRow(v, t.keyOrder)); err != nil {
+			return fmt.Errorf("error appending row to table: %w", err)
 		}
 	}
+	return nil
+}
 
-	csvWriter.Flush()
-	if err := csvWriter.Error(); err != nil {
-		return fmt.Errorf("error flushing csv writer: %w", err)
+func (t *tableEventWriter) Close() error {
+	if err := t.table.Render(); err != nil {
+		return fmt.Errorf("error rendering table: %w", err)
 	}
-	fmt.Print(buf.String())
+	_, err := t.buf.WriteTo(t.w)
+	return err
+}
+
+// jsonEventWriter writes events as a JSON array. A single event is written
+// as a JSON object, therefore the first event is held back until the second
+// one arrives.
+type jsonEventWriter struct {
+	w     io.Writer
+	first *events.Event
+	count int
+}
 
+func (j *jsonEventWriter) Write(allEvents []events.Event) error {
+	for _, evt := range allEvents {
+		j.count++
+		switch j.count {
+		case 1:
+			j.first = &evt
+			continue
+		case 2:
+			if _, err := io.WriteString(j.w, "[\n"); err != nil {
+				return err
+			}
+			if err := j.writeItem(*j.first); err != nil {
+				return err
+			}
+			j.first = nil
+		}
+		if _, err := io.WriteString(j.w, ",\n"); err != nil {
+			return err
+		}
+		if err := j.writeItem(evt); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func printJSON(allEvents []events.Event) error {
-	if len(allEvents) > 1 {
-		jsonEvents, err := json.MarshalIndent(allEvents, "", "  ")
+func (j *jsonEventWriter) writeItem(evt events.Event) error {
+	jsonEvent, err := json.MarshalIndent(evt, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "  %s", jsonEvent)
+	return err
+}
+
+func (j *jsonEventWriter) Close() error {
+	if j.first != nil {
+		jsonEvent, err := json.MarshalIndent(j.first, "", "  ")
 		if err != nil {
 			return err
 		}
-		fmt.Printf("%s\n", jsonEvents)
-	} else if len(allEvents) == 1 {
-		jsonEvent, err := json.MarshalIndent(allEvents[0], "", "  ")
-		if err != nil {
+		_, err = fmt.Fprintf(j.w, "%s\n", jsonEvent)
+		return err
+	}
+	if j.count > 1 {
+		_, err := io.WriteString(j.w, "\n]\n")
+		return err
+	}
+	return nil
+}
+
+// ndjsonEventWriter writes one JSON object per line
+type ndjsonEventWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonEventWriter) Write(allEvents []events.Event) error {
+	enc := json.NewEncoder(n.w)
+	for _, evt := range allEvents {
+		if err := enc.Encode(evt); err != nil {
 			return err
 		}
-		fmt.Printf("%s\n", jsonEvent)
 	}
 	return nil
 }
 
-func printYAML(allEvents []events.Event) error {
-	if len(allEvents) > 1 {
-		yamlEvents, err := yaml.Marshal(allEvents)
+func (n *ndjsonEventWriter) Close() error {
+	return nil
+}
+
+// yamlEventWriter writes events as a YAML sequence. A single event is
+// written as a YAML mapping, therefore the first event is held back until
+// the second one arrives.
+type yamlEventWriter struct {
+	w     io.Writer
+	first *events.Event
+	count int
+}
+
+func (y *yamlEventWriter) Write(allEvents []events.Event) error {
+	for _, evt := range allEvents {
+		y.count++
+		switch y.count {
+		case 1:
+			y.first = &evt
+			continue
+		case 2:
+			if err := y.writeItem(*y.first); err != nil {
+				return err
+			}
+			y.first = nil
+		}
+		if err := y.writeItem(evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (y *yamlEventWriter) writeItem(evt events.Event) error {
+	yamlEvent, err := yaml.Marshal([]events.Event{evt})
+	if err != nil {
+		return err
+	}
+	_, err = y.w.Write(yamlEvent)
+	return err
+}
+
+func (y *yamlEventWriter) Close() error {
+	if y.first != nil {
+		yamlEvent, err := yaml.Marshal(y.first)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("%s", yamlEvents)
-	} else if len(allEvents) == 1 {
-		yamlEvent, err := yaml.Marshal(allEvents[0])
+		_, err = y.w.Write(yamlEvent)
+		return err
+	}
+	return nil
+}
+
+// yamlStreamEventWriter writes each event as a separate YAML document, which
+// allows to consume the output while events are still written.
+type yamlStreamEventWriter struct {
+	w io.Writer
+}
+
+func (y *yamlStreamEventWriter) Write(allEvents []events.Event) error {
+	for _, evt := range allEvents {
+		yamlEvent, err := yaml.Marshal(evt)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("%s", yamlEvent)
+		if _, err := fmt.Fprintf(y.w, "---\n%s", yamlEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (y *yamlStreamEventWriter) Close() error {
+	return nil
+}
+
+type csvEventWriter struct {
+	w             *csv.Writer
+	keyOrder      []string
+	headerWritten bool
+}
+
+func (c *csvEventWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	if err := c.w.Write(c.keyOrder); err != nil {
+		return fmt.Errorf("error writing header to csv: %w", err)
 	}
 	return nil
 }
 
-func printValue(allEvents []events.Event, keyOrder []string) error {
+func (c *csvEventWriter) Write(allEvents []events.Event) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
 	for _, v := range allEvents {
-		kv := eventToKV(v)
-		var p []string
-		for _, k := range keyOrder {
-			v := kv[k]
-			p = append(p, v)
+		if err := c.w.Write(eventToRow(v, c.keyOrder)); err != nil {
+			return fmt.Errorf("error writing record to csv: %w", err)
 		}
-		fmt.Printf("%s\n", strings.Join(p, " "))
 	}
+
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("error flushing csv writer: %w", err)
+	}
+
+	return nil
+}
+
+func (c *csvEventWriter) Close() error {
+	// the header is written even when there are no events
+	return c.Write(nil)
+}
+
+type valueEventWriter struct {
+	w        io.Writer
+	keyOrder []string
+}
+
+func (v *valueEventWriter) Write(allEvents []events.Event) error {
+	for _, evt := range allEvents {
+		if _, err := fmt.Fprintf(v.w, "%s\n", strings.Join(eventToRow(evt, v.keyOrder), " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *valueEventWriter) Close() error {
 	return nil
 }
 
@@ -176,12 +394,7 @@ func writeCSV(w io.Writer, allEvents []events.Event, keyOrder []string) error {
 	}
 
 	for idx, event := range allEvents {
-		kv := eventToKV(event)
-		row := make([]string, len(keyOrder))
-		for i, key := range keyOrder {
-			row[i] = kv[key]
-		}
-		if err := csvWriter.Write(row); err != nil {
+		if err := csvWriter.Write(eventToRow(event, keyOrder)); err != nil {
 			return fmt.Errorf("error writing CSV row %d: %w", idx+1, err)
 		}
 	}
@@ -4,10 +4,13 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/sapcc/go-api-declarations/cadf"
 	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"gopkg.in/yaml.v3"
 )
 
 func TestEventToKV(t *testing.T) {
@@ -30,6 +33,8 @@ func TestEventToKV(t *testing.T) {
 				Agent:   "InitiatorAgent",
 			},
 			AppCredentialID: "InitiatorAppCredentialID",
+			RequestID:       "req-1",
+			GlobalRequestID: "req-0",
 		},
 		RequestPath: "RequestPath",
 	}
@@ -49,6 +54,8 @@ func TestEventToKV(t *testing.T) {
 		"InitiatorAddress":       "InitiatorAddress",
 		"InitiatorAgent":         "InitiatorAgent",
 		"InitiatorAppCredential": "InitiatorAppCredentialID",
+		"RequestID":              "req-1",
+		"GlobalRequestID":        "req-0",
 		"RequestPath":            "RequestPath",
 	}
 
@@ -60,3 +67,45 @@ func TestEventToKV(t *testing.T) {
 		}
 	}
 }
+
+func TestStreamingWriters(t *testing.T) {
+	allEvents := []events.Event{
+		{ID: "1", Action: cadf.Action("create")},
+		{ID: "2", Action: cadf.Action("update")},
+		{ID: "3", Action: cadf.Action("delete")},
+	}
+
+	jsonEvents, err := json.MarshalIndent(allEvents, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlEvents, err := yaml.Marshal(allEvents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"json": string(jsonEvents) + "\n",
+		"yaml": string(yamlEvents),
+	}
+
+	for format, want := range expected {
+		var buf bytes.Buffer
+		w, err := newEventWriter(&buf, format, defaultListKeyOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// write the events in two pages
+		if err := w.Write(allEvents[:1]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Write(allEvents[1:]); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != want {
+			t.Errorf("unexpected %s output:\n%s\nexpected:\n%s", format, buf.String(), want)
+		}
+	}
+}
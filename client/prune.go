@@ -0,0 +1,236 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.xyrillian.de/schwift/v2"
+)
+
+// trimExportExtension returns the object name without the format, the
+// compression and the encryption extensions. Export files with the same
+// name in different formats share the segments prefix of this name.
+func trimExportExtension(name string) string {
+	var longest string
+	for _, ext := range exportFileExtensions() {
+		if strings.HasSuffix(name, ext) && len(ext) > len(longest) {
+			longest = ext
+		}
+	}
+	return strings.TrimSuffix(name, longest)
+}
+
+// findPrunableExports returns the export files below the prefix, which were
+// modified before the cutoff, and all objects to delete with them: their
+// segments, signatures and integrity manifests and the manifests of
+// partitioned exports without any remaining partition. Shared containers may
+// contain objects of other tools, therefore only files with an integrity
+// manifest or with the hermescli marker are considered exports and only the
+// segments referenced by their manifests are deleted.
+func findPrunableExports(ctx context.Context, container *schwift.Container, prefix string, cutoff time.Time) (exports []string, objects []schwift.ObjectInfo, err error) {
+	iter := container.Objects()
+	iter.Prefix = prefix
+	infos, err := iter.CollectDetailed(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	byName := make(map[string]schwift.ObjectInfo, len(infos))
+	for _, info := range infos {
+		byName[info.Object.Name()] = info
+	}
+
+	// kept contains the file names of the export files, which are not pruned,
+	// the manifests of their partitioned exports are kept
+	kept := make(map[string]bool)
+	for _, info := range infos {
+		name := info.Object.Name()
+		if !isExportFile(strings.TrimPrefix(name, prefix)) {
+			continue
+		}
+		if !info.LastModified.Before(cutoff) {
+			kept[trimExportExtension(name)] = true
+			continue
+		}
+		// exports without the marker were written before it was introduced
+		// and are identified by their integrity manifest
+		isExport := false
+		if _, ok := byName[integrityManifestName(name)]; ok {
+			isExport = true
+		} else if isExport, err = hasExportMarker(ctx, info.Object); err != nil {
+			return nil, nil, err
+		}
+		if isExport {
+			exports = append(exports, name)
+		}
+	}
+
+	deleted := make(map[string]bool)
+	add := func(name string) {
+		if info, ok := byName[name]; ok && !deleted[name] {
+			deleted[name] = true
+			objects = append(objects, info)
+		}
+	}
+	// addWithSegments adds the object and the segments referenced by its
+	// manifest
+	addWithSegments := func(name string) error {
+		info, ok := byName[name]
+		if !ok {
+			return nil
+		}
+		add(name)
+		lo, err := info.Object.AsLargeObject(ctx)
+		if errors.Is(err, schwift.ErrNotLarge) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load the %s manifest: %w", name, err)
+		}
+		for _, obj := range lo.SegmentObjects() {
+			if obj.Container().Name() == container.Name() {
+				add(obj.Name())
+			}
+		}
+		return nil
+	}
+
+	for _, name := range exports {
+		for _, obj := range []string{
+			name,
+			name + "." + string(signatureFormat),
+			integrityManifestName(name),
+			integrityManifestName(name) + "." + string(signatureFormat),
+		} {
+			if err := addWithSegments(obj); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// manifests of partitioned exports are deleted together with the last
+	// partition
+	for _, info := range infos {
+		name := info.Object.Name()
+		if path.Base(name) != "manifest."+string(ExportFormatJSON) || !info.LastModified.Before(cutoff) {
+			continue
+		}
+		dir := path.Dir(name) + "/"
+		remaining := false
+		for fileName := range kept {
+			if strings.HasPrefix(fileName, dir) {
+				remaining = true
+				break
+			}
+		}
+		if remaining {
+			continue
+		}
+		marked, err := hasExportMarker(ctx, info.Object)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !marked {
+			continue
+		}
+		for _, obj := range []string{name, name + "." + string(signatureFormat)} {
+			if err := addWithSegments(obj); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return exports, objects, nil
+}
+
+// ExportsPruneCmd represents the exports prune command
+var ExportsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Args:  cobra.NoArgs,
+	Short: "Delete old exports from Swift",
+	Long: `Delete export files from a Swift container, which were modified before --older-than, together
+with their segments, signatures and integrity manifests. Only files uploaded by hermescli or with an
+integrity manifest are deleted.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		if spec.Kind != destinationSwift {
+			return errors.New("only Swift destinations can be pruned")
+		}
+		if viper.GetString("older-than") == "" {
+			return errors.New("older-than flag is required")
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		dryRun := viper.GetBool("dry-run")
+		verb := "Deleted"
+		if dryRun {
+			verb = "Found"
+		}
+
+		cutoff, err := parseTimeExpr(viper.GetString("older-than"), time.Now(), timezone)
+		if err != nil {
+			return fmt.Errorf("failed to parse older-than: %w", err)
+		}
+
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+		container, err := InitializeSwiftContainer(ctx, client.ProviderClient, spec.Container)
+		if err != nil {
+			return fmt.Errorf("failed to initialize Swift container: %w", err)
+		}
+
+		exports, infos, err := findPrunableExports(ctx, container, spec.Prefix, cutoff)
+		if err != nil {
+			return err
+		}
+		var size uint64
+		objects := make([]*schwift.Object, 0, len(infos))
+		for _, info := range infos {
+			size += info.SizeBytes
+			objects = append(objects, info.Object)
+		}
+		for _, name := range exports {
+			fmt.Println(name)
+		}
+		if !dryRun && len(objects) > 0 {
+			if _, _, err := container.Account().BulkDelete(ctx, objects, nil, nil); err != nil {
+				return fmt.Errorf("failed to delete exports: %w", err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%s %d exports in %d objects with %.1fMB\n", verb, len(exports), len(objects), float64(size)/1024/1024)
+
+		return nil
+	},
+}
+
+func init() {
+	ExportsPruneCmd.Flags().StringP("output", "o", "", "Swift location of the exports: swift://container/prefix")
+	ExportsPruneCmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
+	ExportsPruneCmd.Flags().StringP("older-than", "", "", "delete exports modified before this time, e.g. 90d or 2024-05-01")
+	ExportsPruneCmd.Flags().Bool("dry-run", false, "print the exports instead of deleting them")
+	ExportsCmd.AddCommand(ExportsPruneCmd)
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTrimExportExtension(t *testing.T) {
+	cases := map[string]string{
+		"hermes-export.json":         "hermes-export",
+		"2024/hermes-export.csv.gz":  "2024/hermes-export",
+		"hermes-export.yaml.zst.age": "hermes-export",
+		"hermes-export.json.age":     "hermes-export",
+	}
+	for name, expected := range cases {
+		if result := trimExportExtension(name); result != expected {
+			t.Errorf("expected %q to be trimmed to %q, got %q", name, expected, result)
+		}
+	}
+}
+
+func TestFindPrunableExports(t *testing.T) {
+	cutoff := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	old := cutoff.Add(-time.Hour)
+	marked := map[string]string{exportMarkerHeader: "true"}
+
+	container := newFakeSwiftContainer(t, map[string]fakeSwiftObject{
+		"2024/hermes-export.json.gz": {
+			Headers:      marked,
+			Segments:     []string{"2024/hermes-export-segments/0000000000000001"},
+			LastModified: old,
+		},
+		"2024/hermes-export-segments/0000000000000001": {Headers: marked, LastModified: old},
+		"2024/hermes-export.json.gz.sig":               {Headers: marked, LastModified: old},
+		"2024/hermes-export.json.gz.manifest.json":     {Headers: marked, LastModified: old},
+		// exported before the marker was introduced
+		"2024/legacy.json":               {LastModified: old},
+		"2024/legacy.json.manifest.json": {LastModified: old},
+		"2024/manifest.json":             {Headers: marked, LastModified: old},
+		// a partition of the 2025 export remains
+		"2025/hermes-export.json": {Headers: marked, LastModified: cutoff.Add(time.Hour)},
+		"2025/manifest.json":      {Headers: marked, LastModified: old},
+		// foreign objects share the container and the naming
+		"2024/hermes-export-segments/backup": {LastModified: old},
+		"2024/backup.json": {
+			Segments:     []string{"2024/backup-segments/0000000000000001"},
+			LastModified: old,
+		},
+		"2024/backup-segments/0000000000000001": {LastModified: old},
+		"reports/data.csv":                      {LastModified: old},
+		"reports/manifest.json":                 {LastModified: old},
+	})
+
+	exports, objects, err := findPrunableExports(context.Background(), container, "", cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedExports := []string{"2024/hermes-export.json.gz", "2024/legacy.json"}
+	if !slices.Equal(exports, expectedExports) {
+		t.Errorf("expected the exports %v, got %v", expectedExports, exports)
+	}
+
+	var result []string
+	for _, info := range objects {
+		result = append(result, info.Object.Name())
+	}
+	slices.Sort(result)
+	expectedObjects := []string{
+		"2024/hermes-export-segments/0000000000000001",
+		"2024/hermes-export.json.gz",
+		"2024/hermes-export.json.gz.manifest.json",
+		"2024/hermes-export.json.gz.sig",
+		"2024/legacy.json",
+		"2024/legacy.json.manifest.json",
+		"2024/manifest.json",
+	}
+	if !slices.Equal(result, expectedObjects) {
+		t.Errorf("expected the objects %v, got %v", expectedObjects, result)
+	}
+}
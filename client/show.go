@@ -10,6 +10,7 @@ import (
 	"os"
 
 	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/olekukonko/tablewriter"
 	"github.com/olekukonko/tablewriter/tw"
 	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
@@ -31,10 +32,25 @@ var defaultShowKeyOrder = []string{
 	"InitiatorAddress",
 	"InitiatorAgent",
 	"InitiatorAppCredential",
+	"RequestID",
+	"GlobalRequestID",
 	"RequestPath",
 	"Attachments",
 }
 
+// cachedEventInScope returns true, when Hermes would return the cached event
+// for the project or domain scope. Without a scope flag, Hermes uses the
+// scope of the token, which is known only, when it is configured by ID.
+func cachedEventInScope(evt events.Event, projectID, domainID string) bool {
+	if projectID == "" && domainID == "" {
+		projectID = os.Getenv("OS_PROJECT_ID")
+		if projectID == "" {
+			domainID = os.Getenv("OS_DOMAIN_ID")
+		}
+	}
+	return matchEvent(evt, events.ListOpts{ProjectID: projectID, DomainID: domainID})
+}
+
 // ShowCmd represents the show command
 var ShowCmd = &cobra.Command{
 	Use:   "show <event-id> [<event-id>...]",
@@ -49,9 +65,23 @@ var ShowCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// show event
-		client, err := NewHermesV1Client(cmd.Context())
-		if err != nil {
-			return fmt.Errorf("failed to create Hermes client: %w", err)
+		useCache := viper.GetBool("cache") || viper.GetBool("offline")
+		var cache *eventCache
+		if useCache {
+			var err error
+			cache, err = openEventCache()
+			if err != nil {
+				return err
+			}
+		}
+
+		var client *gophercloud.ServiceClient
+		if !viper.GetBool("offline") {
+			var err error
+			client, err = NewHermesV1Client(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to create Hermes client: %w", err)
+			}
 		}
 
 		keyOrder := viper.GetStringSlice("column")
@@ -68,13 +98,10 @@ var ShowCmd = &cobra.Command{
 			bar.Start()
 		}
 
-		projectID := viper.GetString("project-id")
-		if viper.GetBool("all-projects") {
-			projectID = "*"
-		}
-
+		projectID, domainID := getScope()
 		getOpts := events.GetOpts{
 			ProjectID: projectID,
+			DomainID:  domainID,
 		}
 
 		var allEvents []events.Event
@@ -82,11 +109,30 @@ var ShowCmd = &cobra.Command{
 			if bar != nil {
 				bar.SetCurrent(int64(i + 1))
 			}
+			if cache != nil {
+				event, ok, err := cache.GetEvent(id)
+				if err != nil {
+					log.Printf("[WARNING] Failed to get cached %s event: %s", id, err)
+				}
+				if ok && cachedEventInScope(*event, projectID, domainID) {
+					allEvents = append(allEvents, *event)
+					continue
+				}
+				if client == nil {
+					log.Printf("[WARNING] The %s event is not cached in the requested scope", id)
+					continue
+				}
+			}
 			event, err := events.Get(cmd.Context(), client, id, getOpts).Extract()
 			if err != nil {
 				log.Printf("[WARNING] Failed to get %s event: %s", id, err)
 				continue
 			}
+			if cache != nil {
+				if err := cache.PutEvents([]events.Event{*event}); err != nil {
+					log.Printf("[WARNING] Failed to cache %s event: %s", id, err)
+				}
+			}
 			allEvents = append(allEvents, *event)
 		}
 
@@ -110,7 +156,7 @@ var ShowCmd = &cobra.Command{
 
 				// populate output table
 				for _, k := range keyOrder {
-					if v, ok := kv[k]; ok {
+					if v, ok := eventColumnValue(event, kv, k); ok {
 						if err := table.Append(k, v); err != nil {
 							log.Printf("Error appending row for key %s: %v", k, err)
 						}
@@ -137,6 +183,7 @@ func init() {
 }
 
 func initShowCmdFlags() {
-	ShowCmd.Flags().StringP("project-id", "", "", "show event for the project or domain ID (admin only)")
-	ShowCmd.Flags().BoolP("all-projects", "A", false, "include all projects and domains (admin only) (alias for --project-id '*')")
+	addScopeFlags(ShowCmd.Flags(), "show event for")
+	ShowCmd.Flags().BoolP("cache", "", false, "look up events in the local cache first and store fetched events in the cache")
+	ShowCmd.Flags().BoolP("offline", "", false, "show events from the local cache only")
 }
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// signatureFormat is the extension of the detached signature, which is stored
+// next to every signed object
+const signatureFormat ExportFormat = "sig"
+
+// signatureContext separates export signatures from other uses of the key
+const signatureContext = "hermescli-export-signature-v1"
+
+// signatureMessage returns the signed message of an object. The object name
+// is signed as well to prevent swapping objects with their signatures.
+func signatureMessage(objectName, sha256sum string) []byte {
+	return []byte(signatureContext + "\n" + objectName + "\n" + sha256sum + "\n")
+}
+
+// readKeyMaterial reads a key from the file or from the standard output of
+// the command.
+func readKeyMaterial(path, command string) ([]byte, error) {
+	switch {
+	case path != "" && command != "":
+		return nil, errors.New("a key file and a key command cannot be both specified")
+	case command != "":
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute key command: %w", err)
+		}
+		return out, nil
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key: %w", err)
+		}
+		return data, nil
+	}
+	return nil, errors.New("a key file or a key command is required")
+}
+
+// parseSigningKey parses an ed25519 private key in the PKCS #8 PEM format,
+// e.g. generated by "openssl genpkey -algorithm ed25519", or a base64 encoded
+// seed or private key.
+func parseSigningKey(data []byte) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported %T signing key, only ed25519 keys are supported", key)
+		}
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	}
+	return nil, fmt.Errorf("invalid signing key size: %d bytes", len(raw))
+}
+
+// parsePublicKey parses an ed25519 public key in the PKIX PEM format, e.g.
+// generated by "openssl pkey -pubout", or a base64 encoded public key.
+func parsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported %T public key, only ed25519 keys are supported", key)
+		}
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: %d bytes", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// signingDestination writes a detached signature next to every uploaded
+// object, including the manifests.
+type signingDestination struct {
+	exportDestination
+	Key ed25519.PrivateKey
+}
+
+func (d signingDestination) Upload(ctx context.Context, f ExportFile) error {
+	hr := newHashingReader(f.Contents)
+	f.Contents = hr
+	if err := d.exportDestination.Upload(ctx, f); err != nil {
+		return err
+	}
+
+	sig := ed25519.Sign(d.Key, signatureMessage(f.ObjectName(), hr.Sum()))
+	sigFile := ExportFile{
+		Format:      signatureFormat,
+		FileName:    f.ObjectName(),
+		SegmentSize: f.SegmentSize,
+		DeleteAt:    f.DeleteAt,
+		Contents:    strings.NewReader(base64.StdEncoding.EncodeToString(sig) + "\n"),
+	}
+	if err := d.exportDestination.Upload(ctx, sigFile); err != nil {
+		return fmt.Errorf("failed to write signature: %w", err)
+	}
+	return nil
+}
+
+// verifySignature verifies the detached signature of the object with the
+// given SHA-256.
+func verifySignature(ctx context.Context, destination exportDestination, key ed25519.PublicKey, name, objectName, sha256sum string) (integrityCheck, error) {
+	check := integrityCheck{Name: name, Expected: "valid"}
+
+	r, err := destination.Open(ctx, objectName+"."+string(signatureFormat))
+	if isNotFound(err) {
+		check.Actual = "missing"
+		return check, nil
+	}
+	if err != nil {
+		return check, err
+	}
+	defer r.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return check, fmt.Errorf("failed to read signature: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil || !ed25519.Verify(key, signatureMessage(objectName, sha256sum), sig) {
+		check.Actual = "invalid"
+		return check, nil
+	}
+
+	check.Actual = "valid"
+	check.OK = true
+	return check, nil
+}
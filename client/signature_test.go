@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privInputs := map[string][]byte{
+		"PEM":  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+		"seed": []byte(base64.StdEncoding.EncodeToString(priv.Seed()) + "\n"),
+		"key":  []byte(base64.StdEncoding.EncodeToString(priv)),
+	}
+	for name, input := range privInputs {
+		key, err := parseSigningKey(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !key.Equal(priv) {
+			t.Errorf("%s: unexpected signing key", name)
+		}
+	}
+
+	pubInputs := map[string][]byte{
+		"PEM": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		"key": []byte(base64.StdEncoding.EncodeToString(pub)),
+	}
+	for name, input := range pubInputs {
+		key, err := parsePublicKey(input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !key.Equal(pub) {
+			t.Errorf("%s: unexpected public key", name)
+		}
+	}
+
+	if _, err := parseSigningKey([]byte("c2hvcnQ=")); err == nil {
+		t.Error("expected an error for a short signing key")
+	}
+}
+
+func TestSigningDestination(t *testing.T) {
+	ctx := context.Background()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := fileDestination{Dir: t.TempDir()}
+	destination := signingDestination{exportDestination: files, Key: priv}
+	f := ExportFile{Format: ExportFormatJSON, FileName: "export", Contents: strings.NewReader(`[]`)}
+	if err := destination.Upload(ctx, f); err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(`[]`))
+	sum := hex.EncodeToString(digest[:])
+
+	tests := []struct {
+		name     string
+		key      ed25519.PublicKey
+		object   string
+		sha256   string
+		expected string
+	}{
+		{"valid", pub, f.ObjectName(), sum, "valid"},
+		{"other key", otherPub, f.ObjectName(), sum, "invalid"},
+		{"other content", pub, f.ObjectName(), strings.Repeat("0", 64), "invalid"},
+		{"missing", pub, "other.json", sum, "missing"},
+	}
+	for _, tc := range tests {
+		check, err := verifySignature(ctx, files, tc.key, "Signature", tc.object, tc.sha256)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+			continue
+		}
+		if check.Actual != tc.expected || check.OK != (tc.expected == "valid") {
+			t.Errorf("%s: expected %s signature, got %+v", tc.name, tc.expected, check)
+		}
+	}
+
+	if _, err := os.Stat(files.Location(ExportFile{Format: signatureFormat, FileName: f.ObjectName()})); err != nil {
+		t.Errorf("expected a signature file: %s", err)
+	}
+}
@@ -0,0 +1,504 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/gophercloud/gophercloud/v2/pagination"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/attributes"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// otherGroup is the group value of events, which are not covered by the
+// attribute values reported by Hermes
+const otherGroup = "(other)"
+
+var statsPrintFormats = []string{
+	"table",
+	"json",
+	"csv",
+}
+
+// dayBucket is the size of day histogram buckets, which start at midnight
+const dayBucket = 24 * time.Hour
+
+// histogramBuckets contains the supported histogram bucket sizes
+var histogramBuckets = map[string]time.Duration{
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    dayBucket,
+}
+
+// statsRow is a single aggregated row of the stats output
+type statsRow struct {
+	Group []string
+	// Time is the start of the histogram bucket, zero without a histogram
+	Time  time.Time
+	Count int
+}
+
+// statsQuery is a single server-side count query
+type statsQuery struct {
+	Group  []string
+	Bucket *timeWindow
+}
+
+// setAttributeFilter sets the list options filter for the Hermes attribute.
+func setAttributeFilter(listOpts *events.ListOpts, name, value string) {
+	switch name {
+	case "observer_type":
+		listOpts.ObserverType = value
+	case "target_type":
+		listOpts.TargetType = value
+	case "target_id":
+		listOpts.TargetID = value
+	case "initiator_type":
+		listOpts.InitiatorType = value
+	case "initiator_id":
+		listOpts.InitiatorID = value
+	case "initiator_name":
+		listOpts.InitiatorName = value
+	case "action":
+		listOpts.Action = value
+	case "outcome":
+		listOpts.Outcome = value
+	}
+}
+
+// getAttributeFilter returns the list options filter for the Hermes attribute.
+func getAttributeFilter(listOpts events.ListOpts, name string) string {
+	switch name {
+	case "observer_type":
+		return listOpts.ObserverType
+	case "target_type":
+		return listOpts.TargetType
+	case "target_id":
+		return listOpts.TargetID
+	case "initiator_type":
+		return listOpts.InitiatorType
+	case "initiator_id":
+		return listOpts.InitiatorID
+	case "initiator_name":
+		return listOpts.InitiatorName
+	case "action":
+		return listOpts.Action
+	case "outcome":
+		return listOpts.Outcome
+	}
+	return ""
+}
+
+// bucketStart returns the start of the histogram bucket containing t.
+// Buckets are aligned to the wall clock of the time zone, which may be offset
+// by half an hour from UTC, e.g. in Asia/Kolkata.
+func bucketStart(t time.Time, bucket time.Duration, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	if bucket == dayBucket {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(bucket).Add(-shift)
+}
+
+// nextBucketStart returns the start of the histogram bucket following the
+// bucket starting at start. Days are not always 24 hours long, e.g. on
+// daylight saving time changes.
+func nextBucketStart(start time.Time, bucket time.Duration) time.Time {
+	if bucket == dayBucket {
+		return time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+	}
+	return start.Add(bucket)
+}
+
+// splitBuckets splits the time window into histogram buckets.
+func splitBuckets(w timeWindow, bucket time.Duration, loc *time.Location) []timeWindow {
+	var result []timeWindow
+	for start := bucketStart(w.Start, bucket, loc); start.Before(w.End); start = nextBucketStart(start, bucket) {
+		result = append(result, timeWindow{
+			Start: maxTime(start, w.Start),
+			End:   minTime(nextBucketStart(start, bucket), w.End),
+		})
+	}
+	return result
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// listAttributeValues returns all values of the Hermes attribute in the
+// project or domain scope of the list options.
+func listAttributeValues(ctx context.Context, client *gophercloud.ServiceClient, name string, listOpts events.ListOpts) ([]string, error) {
+	var result []string
+	opts := attributes.ListOpts{
+		ProjectID: listOpts.ProjectID,
+		DomainID:  listOpts.DomainID,
+	}
+	err := attributes.List(client, name, opts).EachPage(ctx, func(ctx context.Context, page pagination.Page) (bool, error) {
+		attrs, err := attributes.ExtractAttributes(page)
+		if err != nil {
+			return false, fmt.Errorf("failed to extract attributes: %w", err)
+		}
+		result = append(result, attrs...)
+		return true, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s attributes: %w", name, err)
+	}
+	return result, nil
+}
+
+// combineGroups returns the cartesian product of the attribute values.
+func combineGroups(values [][]string) [][]string {
+	result := [][]string{{}}
+	for _, v := range values {
+		var next [][]string
+		for _, group := range result {
+			for _, value := range v {
+				next = append(next, append(slices.Clone(group), value))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// countServerSide counts the events with one limit=1 query per group and
+// histogram bucket. Events, which are not covered by the groups, are counted
+// as the other group.
+func countServerSide(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, groupBy []string, queries []statsQuery, concurrency int) ([]statsRow, error) {
+	rows := make([]statsRow, len(queries))
+	err := runParallel(ctx, len(queries), concurrency, func(ctx context.Context, i int) error {
+		q := queries[i]
+		opts := listOpts
+		for j, name := range groupBy {
+			setAttributeFilter(&opts, name, q.Group[j])
+		}
+		if q.Bucket != nil {
+			opts = q.Bucket.applyTo(opts)
+			rows[i].Time = q.Bucket.Start
+		}
+		count, err := countEvents(ctx, client, opts)
+		if err != nil {
+			return err
+		}
+		rows[i].Group = q.Group
+		rows[i].Count = count
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groupBy) > 0 && (len(queries) == 0 || queries[0].Bucket == nil) {
+		total, err := countEvents(ctx, client, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		rows = appendOtherGroup(rows, len(groupBy), total)
+	}
+
+	return rows, nil
+}
+
+// appendOtherGroup appends the events of the total, which are not counted by
+// the rows, as the other group.
+func appendOtherGroup(rows []statsRow, groups, total int) []statsRow {
+	for _, row := range rows {
+		total -= row.Count
+	}
+	if total <= 0 {
+		return rows
+	}
+	group := make([]string, groups)
+	for i := range group {
+		group[i] = otherGroup
+	}
+	return append(rows, statsRow{Group: group, Count: total})
+}
+
+// countLocally downloads the events and counts them per group and histogram
+// bucket.
+func countLocally(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, groupBy []string, bucket time.Duration, concurrency int) ([]statsRow, error) {
+	counts := make(map[string]*statsRow)
+
+	var bar *pb.ProgressBar
+	err := getEvents(ctx, client, listOpts, 0, concurrency, &bar, func(page []events.Event) error {
+		for _, evt := range page {
+			row := statsRow{Group: make([]string, len(groupBy))}
+			for i, name := range groupBy {
+				row.Group[i] = eventAttribute(evt, name)
+			}
+			if bucket > 0 {
+				t, err := parseTime(evt.EventTime)
+				if err != nil {
+					log.Printf("[WARNING] Failed to parse time of the %s event: %s", evt.ID, err)
+					continue
+				}
+				row.Time = bucketStart(t, bucket, timezone)
+			}
+
+			key := strings.Join(row.Group, "\x00") + "\x00" + row.Time.String()
+			if v, ok := counts[key]; ok {
+				v.Count++
+			} else {
+				row.Count = 1
+				counts[key] = &row
+			}
+		}
+		return nil
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]statsRow, 0, len(counts))
+	for _, v := range counts {
+		rows = append(rows, *v)
+	}
+	return rows, nil
+}
+
+func printStats(rows []statsRow, groupBy []string, histogram bool, format string) error {
+	header := slices.Clone(groupBy)
+	if histogram {
+		header = append(header, "time")
+	}
+	header = append(header, "count")
+
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = slices.Clone(row.Group)
+		if histogram {
+			records[i] = append(records[i], row.Time.Format(time.RFC3339))
+		}
+		records[i] = append(records[i], strconv.Itoa(row.Count))
+	}
+
+	switch format {
+	case "table":
+		table := tablewriter.NewTable(os.Stdout,
+			tablewriter.WithHeaderMaxWidth(40),
+			tablewriter.WithRowMaxWidth(40),
+			tablewriter.WithRowAlignment(tw.AlignRight),
+		)
+		table.Header(header)
+		if err := table.Bulk(records); err != nil {
+			return fmt.Errorf("error appending rows to table: %w", err)
+		}
+		if err := table.Render(); err != nil {
+			return fmt.Errorf("error rendering table: %w", err)
+		}
+	case "csv":
+		csvWriter := csv.NewWriter(os.Stdout)
+		if err := csvWriter.Write(header); err != nil {
+			return fmt.Errorf("error writing header to csv: %w", err)
+		}
+		if err := csvWriter.WriteAll(records); err != nil {
+			return fmt.Errorf("error writing records to csv: %w", err)
+		}
+	case "json":
+		result := make([]map[string]any, len(rows))
+		for i, row := range rows {
+			result[i] = map[string]any{"count": row.Count}
+			for j, name := range groupBy {
+				result[i][name] = row.Group[j]
+			}
+			if histogram {
+				result[i]["time"] = row.Time.Format(time.RFC3339)
+			}
+		}
+		jsonStats, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n", jsonStats)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	return nil
+}
+
+// StatsCmd represents the stats command
+var StatsCmd = &cobra.Command{
+	Use:   "stats",
+	Args:  cobra.ExactArgs(0),
+	Short: "Count Hermes events",
+	Long: `Count Hermes events grouped by attributes and time.
+When the amount of required count queries does not exceed --max-queries, the events are counted by Hermes,
+otherwise the events are downloaded and counted locally.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		if err := verifyFilterFlags(); err != nil {
+			return err
+		}
+
+		for _, name := range viper.GetStringSlice("group-by") {
+			if !slices.Contains(validArgs, name) {
+				return fmt.Errorf(`invalid "%s" group, supported values for the group: %s`, name, strings.Join(validArgs, ", "))
+			}
+		}
+
+		histogram := viper.GetString("histogram")
+		if _, ok := histogramBuckets[histogram]; histogram != "" && !ok {
+			return fmt.Errorf(`invalid "%s" histogram, supported values for the histogram: minute, hour, day`, histogram)
+		}
+		if histogram != "" && viper.GetInt("top") > 0 {
+			return errors.New("--top and --histogram cannot be both specified")
+		}
+
+		return verifyGlobalFlags(nil, statsPrintFormats...)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		groupBy := viper.GetStringSlice("group-by")
+		bucket := histogramBuckets[viper.GetString("histogram")]
+		concurrency := viper.GetInt("concurrency")
+
+		listOpts, err := buildListOpts(time.Now())
+		if err != nil {
+			return err
+		}
+
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+
+		// collect the histogram buckets
+		var buckets []timeWindow
+		if bucket > 0 {
+			total, err := countEvents(ctx, client, listOpts)
+			if err != nil {
+				return err
+			}
+			if total > 0 {
+				w, ok, err := getTimeWindow(ctx, client, listOpts)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("cannot build a histogram for the time filter")
+				}
+				buckets = splitBuckets(w, bucket, timezone)
+			}
+		}
+
+		// collect the attribute values of the groups
+		values := make([][]string, len(groupBy))
+		queryCount := max(len(buckets), 1)
+		maxQueries := viper.GetInt("max-queries")
+		for i, name := range groupBy {
+			if queryCount > maxQueries {
+				break
+			}
+			if v := getAttributeFilter(listOpts, name); v != "" {
+				values[i] = []string{v}
+				continue
+			}
+			values[i], err = listAttributeValues(ctx, client, name, listOpts)
+			if err != nil {
+				return err
+			}
+			queryCount *= max(len(values[i]), 1)
+		}
+
+		var rows []statsRow
+		if queryCount <= maxQueries {
+			var queries []statsQuery
+			for _, group := range combineGroups(values) {
+				if len(buckets) == 0 {
+					queries = append(queries, statsQuery{Group: group})
+				}
+				for i := range buckets {
+					queries = append(queries, statsQuery{Group: group, Bucket: &buckets[i]})
+				}
+			}
+			rows, err = countServerSide(ctx, client, listOpts, groupBy, queries, concurrency)
+		} else {
+			fmt.Fprintf(os.Stderr, "More than %d count queries are required, counting events locally...\n", maxQueries)
+			rows, err = countLocally(ctx, client, listOpts, groupBy, bucket, concurrency)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to count events: %w", err)
+		}
+
+		if len(groupBy) > 0 {
+			// drop empty groups
+			rows = slices.DeleteFunc(rows, func(row statsRow) bool { return row.Count == 0 })
+		}
+
+		slices.SortFunc(rows, func(a, b statsRow) int {
+			if bucket == 0 {
+				if c := cmp.Compare(b.Count, a.Count); c != 0 {
+					return c
+				}
+			}
+			if c := slices.Compare(a.Group, b.Group); c != 0 {
+				return c
+			}
+			return a.Time.Compare(b.Time)
+		})
+
+		if top := viper.GetInt("top"); top > 0 && len(rows) > top {
+			rows = rows[:top]
+		}
+
+		return printStats(rows, groupBy, bucket > 0, viper.GetString("format"))
+	},
+}
+
+func init() {
+	initStatsCmdFlags()
+	RootCmd.AddCommand(StatsCmd)
+}
+
+func initStatsCmdFlags() {
+	addFilterFlags(StatsCmd.Flags())
+	addTimeFilterFlags(StatsCmd.Flags())
+	StatsCmd.Flags().StringSliceP("group-by", "g", []string{}, "count events per attribute, supported attributes: "+strings.Join(validArgs, ", "))
+	StatsCmd.Flags().UintP("top", "", 0, "print only the N groups with the most events")
+	StatsCmd.Flags().StringP("histogram", "", "", "count events per time bucket, supported buckets: minute, hour, day")
+	StatsCmd.Flags().UintP("max-queries", "", 200, "maximum amount of count queries, otherwise events are downloaded and counted locally")
+	StatsCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests")
+}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBucketStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data is not available: %s", err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skipf("time zone data is not available: %s", err)
+	}
+
+	cases := []struct {
+		Time     time.Time
+		Bucket   time.Duration
+		Location *time.Location
+		Expected time.Time
+	}{
+		{time.Date(2024, 5, 1, 10, 42, 17, 0, time.UTC), time.Minute, nil, time.Date(2024, 5, 1, 10, 42, 0, 0, time.UTC)},
+		{time.Date(2024, 5, 1, 10, 42, 17, 0, time.UTC), time.Hour, nil, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 5, 1, 10, 42, 17, 0, time.UTC), dayBucket, nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		// 23:30 UTC is already the next day in Berlin
+		{time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC), dayBucket, berlin, time.Date(2024, 5, 2, 0, 0, 0, 0, berlin)},
+		// the DST change day starts at midnight, although it has 23 hours
+		{time.Date(2024, 3, 31, 20, 0, 0, 0, time.UTC), dayBucket, berlin, time.Date(2024, 3, 31, 0, 0, 0, 0, berlin)},
+		// hours start at half past in UTC with a +05:30 offset
+		{time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC), time.Hour, kolkata, time.Date(2024, 5, 1, 15, 0, 0, 0, kolkata)},
+		{time.Date(2024, 5, 1, 10, 45, 0, 0, time.UTC), time.Hour, kolkata, time.Date(2024, 5, 1, 16, 0, 0, 0, kolkata)},
+		{time.Date(2024, 5, 1, 10, 45, 30, 0, time.UTC), time.Minute, kolkata, time.Date(2024, 5, 1, 16, 15, 0, 0, kolkata)},
+	}
+	for _, c := range cases {
+		if result := bucketStart(c.Time, c.Bucket, c.Location); !result.Equal(c.Expected) {
+			t.Errorf("expected the %s bucket of %s to start at %s, got %s", c.Bucket, c.Time, c.Expected, result)
+		}
+	}
+}
+
+func TestNextBucketStart(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data is not available: %s", err)
+	}
+
+	cases := []struct {
+		Start    time.Time
+		Bucket   time.Duration
+		Expected time.Duration
+	}{
+		{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), time.Hour, time.Hour},
+		{time.Date(2024, 5, 1, 0, 0, 0, 0, berlin), dayBucket, 24 * time.Hour},
+		// daylight saving time starts and ends
+		{time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), dayBucket, 23 * time.Hour},
+		{time.Date(2024, 10, 27, 0, 0, 0, 0, berlin), dayBucket, 25 * time.Hour},
+	}
+	for _, c := range cases {
+		if result := nextBucketStart(c.Start, c.Bucket).Sub(c.Start); result != c.Expected {
+			t.Errorf("expected the %s bucket starting at %s to last %s, got %s", c.Bucket, c.Start, c.Expected, result)
+		}
+	}
+}
+
+func TestSplitBuckets(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data is not available: %s", err)
+	}
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	// the window starts and ends inside a bucket
+	buckets := splitBuckets(timeWindow{Start: at(1, 10, 30), End: at(1, 13, 15)}, time.Hour, nil)
+	expected := []timeWindow{
+		{Start: at(1, 10, 30), End: at(1, 11, 0)},
+		{Start: at(1, 11, 0), End: at(1, 12, 0)},
+		{Start: at(1, 12, 0), End: at(1, 13, 0)},
+		{Start: at(1, 13, 0), End: at(1, 13, 15)},
+	}
+	if !slices.EqualFunc(buckets, expected, func(a, b timeWindow) bool {
+		return a.Start.Equal(b.Start) && a.End.Equal(b.End)
+	}) {
+		t.Errorf("expected %v buckets, got %v", expected, buckets)
+	}
+
+	// the window ends at a bucket boundary
+	if buckets := splitBuckets(timeWindow{Start: at(1, 10, 0), End: at(1, 12, 0)}, time.Hour, nil); len(buckets) != 2 {
+		t.Errorf("expected 2 buckets, got %v", buckets)
+	}
+
+	// days across the DST change in the Berlin time zone
+	dstStart := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+	buckets = splitBuckets(timeWindow{Start: dstStart, End: time.Date(2024, 4, 1, 12, 0, 0, 0, berlin)}, dayBucket, berlin)
+	expected = []timeWindow{
+		{Start: dstStart, End: time.Date(2024, 3, 31, 0, 0, 0, 0, berlin)},
+		{Start: time.Date(2024, 3, 31, 0, 0, 0, 0, berlin), End: time.Date(2024, 4, 1, 0, 0, 0, 0, berlin)},
+		{Start: time.Date(2024, 4, 1, 0, 0, 0, 0, berlin), End: time.Date(2024, 4, 1, 12, 0, 0, 0, berlin)},
+	}
+	if !slices.EqualFunc(buckets, expected, func(a, b timeWindow) bool {
+		return a.Start.Equal(b.Start) && a.End.Equal(b.End)
+	}) {
+		t.Errorf("expected %v buckets, got %v", expected, buckets)
+	}
+}
+
+func TestCombineGroups(t *testing.T) {
+	cases := []struct {
+		Values   [][]string
+		Expected [][]string
+	}{
+		{nil, [][]string{{}}},
+		{[][]string{{"create", "delete"}}, [][]string{{"create"}, {"delete"}}},
+		{
+			[][]string{{"create", "delete"}, {"success", "failure"}},
+			[][]string{{"create", "success"}, {"create", "failure"}, {"delete", "success"}, {"delete", "failure"}},
+		},
+		{[][]string{{"create"}, {}}, nil},
+	}
+	for _, c := range cases {
+		result := combineGroups(c.Values)
+		if !slices.EqualFunc(result, c.Expected, slices.Equal) {
+			t.Errorf("expected %v to be combined to %v, got %v", c.Values, c.Expected, result)
+		}
+	}
+}
+
+func TestAppendOtherGroup(t *testing.T) {
+	rows := []statsRow{
+		{Group: []string{"create", "success"}, Count: 5},
+		{Group: []string{"delete", "failure"}, Count: 3},
+	}
+
+	result := appendOtherGroup(slices.Clone(rows), 2, 10)
+	if len(result) != 3 {
+		t.Fatalf("expected an other group, got %v", result)
+	}
+	other := result[2]
+	if !slices.Equal(other.Group, []string{otherGroup, otherGroup}) || other.Count != 2 {
+		t.Errorf("expected 2 events in the other group, got %v", other)
+	}
+
+	if result := appendOtherGroup(slices.Clone(rows), 2, 8); len(result) != 2 {
+		t.Errorf("expected no other group, when all events are counted, got %v", result)
+	}
+}
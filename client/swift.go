@@ -5,10 +5,16 @@ package client
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec // Swift Etags use md5
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/gophercloud/v2/openstack"
@@ -17,21 +23,65 @@ import (
 	"go.xyrillian.de/schwift/v2/gopherschwift"
 )
 
+// errSegmentMismatch is returned, when the contents do not match the
+// segments of an interrupted upload
+var errSegmentMismatch = errors.New("contents do not match the previously uploaded segments")
+
+// exportMarkerHeader marks the manifests and the segments uploaded by
+// hermescli. Shared containers may contain objects of other tools, therefore
+// only marked objects are removed by the export cleanup and prune commands.
+const exportMarkerHeader = "X-Object-Meta-Hermescli-Export"
+
 // ExportFile represents a file to be exported to Swift storage
 type ExportFile struct {
 	Format      ExportFormat
+	Compression Compression
 	FileName    string
 	SegmentSize uint64
 	Contents    io.Reader
+	// Encryption contains the recipients of encrypted contents
+	Encryption []string
+	// DeleteAt is the expiry time of the manifest and of all segments, zero
+	// keeps the file forever
+	DeleteAt time.Time
+	// Segments were uploaded by an interrupted upload of the same contents
+	// and are reused instead of being uploaded again
+	Segments []UploadedSegment
+	// OnSegment is called after each uploaded segment
+	OnSegment func(UploadedSegment) error
+}
+
+// UploadedSegment is a segment object of a static large object
+type UploadedSegment struct {
+	Name      string `json:"name"`
+	SizeBytes uint64 `json:"size_bytes"`
+	Etag      string `json:"etag"`
+}
+
+// ObjectName returns the file name including the format, the compression
+// and the encryption extensions
+func (f ExportFile) ObjectName() string {
+	name := fmt.Sprintf("%s.%s%s", f.FileName, f.Format, f.Compression.Extension())
+	if len(f.Encryption) > 0 {
+		name += encryptionExtension
+	}
+	return name
 }
 
 func (f ExportFile) UploadTo(ctx context.Context, container *schwift.Container) error {
-	filename := fmt.Sprintf("%s.%s", f.FileName, f.Format)
-	obj := container.Object(filename)
+	obj := container.Object(f.ObjectName())
 
 	// Setup headers
 	headers := make(schwift.Headers)
-	headers.Set("Content-Type", getContentType(f.Format))
+	if len(f.Encryption) > 0 {
+		headers.Set("Content-Type", "application/octet-stream")
+	} else {
+		headers.Set("Content-Type", getContentType(f.Format))
+	}
+	if !f.DeleteAt.IsZero() {
+		headers.Set("X-Delete-At", strconv.FormatInt(f.DeleteAt.Unix(), 10))
+	}
+	headers.Set(exportMarkerHeader, "true")
 
 	// Create segmentation options
 	segmentOpts := schwift.SegmentingOptions{
@@ -51,18 +101,97 @@ func (f ExportFile) UploadTo(ctx context.Context, container *schwift.Container)
 	if segSize > uint64(math.MaxInt64) {
 		return errors.New("segment size exceeds maximum int64 value")
 	}
-	if err := largeObject.Append(ctx, f.Contents, int64(segSize), headers.ToOpts()); err != nil {
-		return fmt.Errorf("failed to upload segments: %w", err)
+	if segSize == 0 {
+		return errors.New("segment size must be positive")
+	}
+
+	// Reuse the segments of an interrupted upload, when the contents match
+	for _, s := range f.Segments {
+		if s.SizeBytes > segSize {
+			return fmt.Errorf("segment %s: %w", s.Name, errSegmentMismatch)
+		}
+		hash := md5.New()                                        //nolint:gosec // Etag uses md5
+		n, err := io.CopyN(hash, f.Contents, int64(s.SizeBytes)) //nolint:gosec // limited by the segment size
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read contents: %w", err)
+		}
+		if n != int64(s.SizeBytes) || hex.EncodeToString(hash.Sum(nil)) != s.Etag { //nolint:gosec // limited by the segment size
+			return fmt.Errorf("segment %s: %w", s.Name, errSegmentMismatch)
+		}
+		err = largeObject.AddSegment(schwift.SegmentInfo{
+			Object:    container.Object(s.Name),
+			SizeBytes: s.SizeBytes,
+			Etag:      s.Etag,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add segment: %w", err)
+		}
+	}
+
+	// Upload one segment at a time to report each uploaded segment
+	for {
+		segments, err := largeObject.Segments()
+		if err != nil {
+			return fmt.Errorf("failed to list segments: %w", err)
+		}
+		count := len(segments)
+
+		if err := largeObject.Append(ctx, io.LimitReader(f.Contents, int64(segSize)), int64(segSize), headers.ToOpts()); err != nil {
+			return fmt.Errorf("failed to upload segments: %w", err)
+		}
+
+		segments, err = largeObject.Segments()
+		if err != nil {
+			return fmt.Errorf("failed to list segments: %w", err)
+		}
+		if len(segments) == count {
+			// all contents are uploaded
+			break
+		}
+
+		if f.OnSegment != nil {
+			last := segments[len(segments)-1]
+			err := f.OnSegment(UploadedSegment{
+				Name:      last.Object.Name(),
+				SizeBytes: last.SizeBytes,
+				Etag:      last.Etag,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Segments are parts of the compressed stream, therefore only the
+	// manifest declares the content encoding. Encrypted contents cannot be
+	// decoded by clients and are described by the metadata instead.
+	if len(f.Encryption) > 0 {
+		headers.Set("X-Object-Meta-Encryption", "age")
+		headers.Set("X-Object-Meta-Encryption-Recipients", strings.Join(f.Encryption, ","))
+	} else if f.Compression != CompressionNone {
+		headers.Set("Content-Encoding", f.Compression.ContentEncoding())
 	}
 
 	// Write the manifest to complete the upload
-	if err := largeObject.WriteManifest(ctx, nil); err != nil {
+	if err := largeObject.WriteManifest(ctx, headers.ToOpts()); err != nil {
 		return fmt.Errorf("failed to write manifest: %w", err)
 	}
 
 	return nil
 }
 
+// hasExportMarker returns true, when the object was uploaded by hermescli
+func hasExportMarker(ctx context.Context, obj *schwift.Object) (bool, error) {
+	hdr, err := obj.Headers(ctx)
+	if schwift.Is(err, http.StatusNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get the %s headers: %w", obj.Name(), err)
+	}
+	return hdr.Get(exportMarkerHeader) != "", nil
+}
+
 // InitializeSwiftContainer creates and initializes a Swift container
 func InitializeSwiftContainer(ctx context.Context, provider *gophercloud.ProviderClient, containerName string) (*schwift.Container, error) {
 	client, err := openstack.NewObjectStorageV1(provider, gophercloud.EndpointOpts{
@@ -94,6 +223,8 @@ func getContentType(format ExportFormat) string {
 		return "text/csv"
 	case ExportFormatYAML:
 		return "application/x-yaml"
+	case signatureFormat:
+		return "text/plain"
 	default:
 		return "application/octet-stream"
 	}
@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.xyrillian.de/schwift/v2"
+)
+
+// fakeSwiftObject is an object of the fake Swift backend. Objects with
+// segments are static large objects.
+type fakeSwiftObject struct {
+	Headers      map[string]string
+	Segments     []string
+	LastModified time.Time
+}
+
+// fakeSwiftBackend serves the objects of a single container from memory. It
+// supports the requests used to find the objects to delete: listings, HEAD
+// requests and the download of static large object manifests.
+type fakeSwiftBackend struct {
+	Objects map[string]fakeSwiftObject
+}
+
+const fakeSwiftContainerName = "exports"
+
+func newFakeSwiftContainer(t *testing.T, objects map[string]fakeSwiftObject) *schwift.Container {
+	account, err := schwift.InitializeAccount(&fakeSwiftBackend{Objects: objects})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.Container(fakeSwiftContainerName)
+}
+
+func (b *fakeSwiftBackend) EndpointURL() string {
+	return "https://swift.example.com/v1/AUTH_test/"
+}
+
+func (b *fakeSwiftBackend) Clone(newEndpointURL string) schwift.Backend {
+	return b
+}
+
+func (b *fakeSwiftBackend) Do(req *http.Request) (*http.Response, error) {
+	containerName, objectName, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/v1/AUTH_test/"), "/")
+	if containerName != fakeSwiftContainerName {
+		return fakeSwiftResponse(http.StatusNotFound, nil, nil), nil
+	}
+	if objectName == "" {
+		return b.list(req)
+	}
+
+	obj, ok := b.Objects[objectName]
+	if !ok {
+		return fakeSwiftResponse(http.StatusNotFound, nil, nil), nil
+	}
+	header := make(http.Header)
+	for k, v := range obj.Headers {
+		header.Set(k, v)
+	}
+	if len(obj.Segments) > 0 {
+		header.Set("X-Static-Large-Object", "True")
+	}
+
+	switch {
+	case req.Method == http.MethodHead:
+		return fakeSwiftResponse(http.StatusOK, header, nil), nil
+	case req.Method == http.MethodGet && req.URL.Query().Get("multipart-manifest") == "get":
+		manifest := make([]map[string]string, 0, len(obj.Segments))
+		for _, name := range obj.Segments {
+			manifest = append(manifest, map[string]string{"path": "/" + fakeSwiftContainerName + "/" + name})
+		}
+		data, err := json.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+		return fakeSwiftResponse(http.StatusOK, header, data), nil
+	}
+	return nil, fmt.Errorf("unexpected %s request of %s", req.Method, req.URL)
+}
+
+// list returns the objects after the marker as a detailed listing
+func (b *fakeSwiftBackend) list(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	type entry struct {
+		Name         string `json:"name"`
+		Bytes        uint64 `json:"bytes"`
+		ContentType  string `json:"content_type"`
+		LastModified string `json:"last_modified"`
+	}
+	result := []entry{}
+	for _, name := range slices.Sorted(maps.Keys(b.Objects)) {
+		if !strings.HasPrefix(name, query.Get("prefix")) || name <= query.Get("marker") {
+			continue
+		}
+		result = append(result, entry{
+			Name:         name,
+			Bytes:        1,
+			ContentType:  "application/octet-stream",
+			LastModified: b.Objects[name].LastModified.UTC().Format("2006-01-02T15:04:05.000000"),
+		})
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return fakeSwiftResponse(http.StatusOK, nil, data), nil
+}
+
+func fakeSwiftResponse(status int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
+
+func TestHasExportMarker(t *testing.T) {
+	container := newFakeSwiftContainer(t, map[string]fakeSwiftObject{
+		"hermes-export.json": {Headers: map[string]string{exportMarkerHeader: "true"}},
+		"backup.json":        {},
+	})
+
+	cases := map[string]bool{
+		"hermes-export.json": true,
+		"backup.json":        false,
+		"missing.json":       false,
+	}
+	for name, expected := range cases {
+		result, err := hasExportMarker(context.Background(), container.Object(name))
+		if err != nil {
+			t.Errorf("failed to check %s: %s", name, err)
+			continue
+		}
+		if result != expected {
+			t.Errorf("expected the marker of %s to be %t, got %t", name, expected, result)
+		}
+	}
+}
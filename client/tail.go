@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// eventWatermark tracks the time of the newest printed event. Hermes filters
+// events by time with a precision of one second, therefore events, which
+// share the watermark second, are requested again on the next poll and
+// skipped using their IDs.
+type eventWatermark struct {
+	Time time.Time
+	IDs  map[string]struct{}
+}
+
+// Filter returns the events, which were not seen yet, and moves the
+// watermark to the newest of them. The events must be sorted by time in
+// ascending order.
+func (w *eventWatermark) Filter(allEvents []events.Event) []events.Event {
+	if w.IDs == nil {
+		w.IDs = make(map[string]struct{})
+	}
+
+	var result []events.Event
+	for _, evt := range allEvents {
+		t, err := parseTime(evt.EventTime)
+		if err != nil {
+			log.Printf("[WARNING] Failed to parse time of the %s event: %s", evt.ID, err)
+			continue
+		}
+		t = t.Truncate(time.Second)
+
+		if t.Before(w.Time) {
+			continue
+		}
+		if t.After(w.Time) {
+			w.Time = t
+			w.IDs = make(map[string]struct{})
+		}
+		if _, ok := w.IDs[evt.ID]; ok {
+			continue
+		}
+		w.IDs[evt.ID] = struct{}{}
+		result = append(result, evt)
+	}
+
+	return result
+}
+
+// newTailEventWriter returns the writer of the followed events. JSON and YAML
+// are written as one document per event, because an array is valid only
+// after the last event. The table format and the list templates are rendered
+// after all events are known, therefore nil is returned and each poll result
+// is rendered separately.
+func newTailEventWriter(w io.Writer, format string, keyOrder []string) (eventWriter, error) {
+	if isListFormat(format) {
+		return nil, nil
+	}
+	switch format {
+	case "table":
+		return nil, nil
+	case "json":
+		return &ndjsonEventWriter{w: w}, nil
+	case "yaml":
+		return &yamlStreamEventWriter{w: w}, nil
+	}
+	return newEventWriter(w, format, keyOrder)
+}
+
+// pollEvents fetches the events newer than the watermark and passes the not
+// yet seen ones to the handler.
+func pollEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, watermark *eventWatermark, concurrency int, handler func([]events.Event) error) error {
+	listOpts.Sort = "time:asc"
+	listOpts.Time = []events.DateQuery{
+		{
+			Date:   watermark.Time,
+			Filter: events.DateFilterGTE,
+		},
+	}
+
+	var bar *pb.ProgressBar
+	err := getEvents(ctx, client, listOpts, 0, concurrency, &bar, func(page []events.Event) error {
+		if newEvents := watermark.Filter(page); len(newEvents) > 0 {
+			return handler(newEvents)
+		}
+		return nil
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+
+	return err
+}
+
+// TailCmd represents the tail command
+var TailCmd = &cobra.Command{
+	Use:   "tail",
+	Args:  cobra.ExactArgs(0),
+	Short: "Follow new Hermes events",
+	Long: `Follow new Hermes events until interrupted.
+Hermes is polled periodically for events newer than the last printed event.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		if err := verifyFilterFlags(); err != nil {
+			return err
+		}
+
+		if viper.GetDuration("interval") <= 0 {
+			return errors.New("--interval must be positive")
+		}
+		if viper.GetDuration("max-backoff") < viper.GetDuration("interval") {
+			return errors.New("--max-backoff cannot be less than --interval")
+		}
+
+		return verifyGlobalFlags(defaultListKeyOrder)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		keyOrder := viper.GetStringSlice("column")
+		if len(keyOrder) == 0 {
+			keyOrder = defaultListKeyOrder
+		}
+		format := viper.GetString("format")
+
+		since := time.Now()
+		if s := viper.GetString("since"); s != "" {
+			rt, err := parseTimeExpr(s, since, timezone)
+			if err != nil {
+				return fmt.Errorf("failed to parse since: %w", err)
+			}
+			since = rt
+		}
+
+		listOpts := buildFilterListOpts()
+
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+
+		w, err := newTailEventWriter(os.Stdout, format, keyOrder)
+		if err != nil {
+			return err
+		}
+
+		handler := func(page []events.Event) error {
+			if w != nil {
+				return w.Write(page)
+			}
+			return printEvent(page, format, keyOrder)
+		}
+
+		watermark := &eventWatermark{Time: since.Truncate(time.Second)}
+		interval := viper.GetDuration("interval")
+		maxBackoff := viper.GetDuration("max-backoff")
+		delay := interval
+
+		for {
+			err := pollEvents(ctx, client, listOpts, watermark, viper.GetInt("concurrency"), handler)
+			switch {
+			case ctx.Err() != nil:
+				// interrupted by the user
+			case err != nil:
+				delay = min(delay*2, maxBackoff)
+				log.Printf("[WARNING] Failed to poll events, retrying in %s: %s", delay, err)
+			default:
+				delay = interval
+			}
+
+			select {
+			case <-ctx.Done():
+				if w != nil {
+					return w.Close()
+				}
+				return nil
+			case <-time.After(delay):
+			}
+		}
+	},
+}
+
+func init() {
+	initTailCmdFlags()
+	RootCmd.AddCommand(TailCmd)
+}
+
+func initTailCmdFlags() {
+	addFilterFlags(TailCmd.Flags())
+	TailCmd.Flags().StringP("since", "", "", "print events starting from time, e.g. 10m or today (default: now)")
+	TailCmd.Flags().DurationP("interval", "i", 10*time.Second, "poll interval")
+	TailCmd.Flags().DurationP("max-backoff", "", 5*time.Minute, "maximum poll interval after consecutive errors")
+	TailCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
+}
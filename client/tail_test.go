@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestEventWatermarkFilter(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w := &eventWatermark{Time: start}
+
+	ids := func(allEvents []events.Event) []string {
+		var result []string
+		for _, evt := range allEvents {
+			result = append(result, evt.ID)
+		}
+		return result
+	}
+
+	cases := []struct {
+		Name     string
+		Events   []events.Event
+		Expected []string
+		Time     time.Time
+	}{
+		{
+			Name: "events older than the watermark",
+			Events: []events.Event{
+				{ID: "old", EventTime: "2024-05-01T11:59:59.900+0000"},
+				{ID: "1", EventTime: "2024-05-01T12:00:00.100+0000"},
+				{ID: "2", EventTime: "2024-05-01T12:00:00.900+0000"},
+			},
+			Expected: []string{"1", "2"},
+			Time:     start,
+		},
+		{
+			Name: "same second events of the previous poll",
+			Events: []events.Event{
+				{ID: "1", EventTime: "2024-05-01T12:00:00.100+0000"},
+				{ID: "2", EventTime: "2024-05-01T12:00:00.900+0000"},
+				{ID: "3", EventTime: "2024-05-01T12:00:00.950+0000"},
+			},
+			Expected: []string{"3"},
+			Time:     start,
+		},
+		{
+			Name: "the watermark moves forward",
+			Events: []events.Event{
+				{ID: "3", EventTime: "2024-05-01T12:00:00.950+0000"},
+				{ID: "4", EventTime: "2024-05-01T12:00:05.500+0000"},
+				{ID: "5", EventTime: "2024-05-01T12:00:05.600+0000"},
+			},
+			Expected: []string{"4", "5"},
+			Time:     start.Add(5 * time.Second),
+		},
+		{
+			Name: "events of the previous watermark second are skipped",
+			Events: []events.Event{
+				{ID: "late", EventTime: "2024-05-01T12:00:00.990+0000"},
+				{ID: "5", EventTime: "2024-05-01T12:00:05.600+0000"},
+				{ID: "6", EventTime: "2024-05-01T12:00:06.000+0000"},
+			},
+			Expected: []string{"6"},
+			Time:     start.Add(6 * time.Second),
+		},
+	}
+	for _, c := range cases {
+		result := ids(w.Filter(c.Events))
+		if !slices.Equal(result, c.Expected) {
+			t.Errorf("%s: expected %v, got %v", c.Name, c.Expected, result)
+		}
+		if !w.Time.Equal(c.Time) {
+			t.Errorf("%s: expected the %s watermark, got %s", c.Name, c.Time, w.Time)
+		}
+	}
+}
+
+func TestTailEventWriter(t *testing.T) {
+	allEvents := []events.Event{{ID: "1"}}
+	cases := map[string]string{
+		"json": `{"typeURI":"","id":"1"`,
+		"yaml": "---\ntypeuri: \"\"\nid: \"1\"\n",
+	}
+	for format, prefix := range cases {
+		var buf bytes.Buffer
+		w, err := newTailEventWriter(&buf, format, defaultListKeyOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a single event must be printed before the writer is closed
+		if err := w.Write(allEvents); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte(prefix)) {
+			t.Errorf("expected the %s output to start with %q, got %q", format, prefix, buf.String())
+		}
+	}
+
+	// formats rendering the whole list are rendered for each poll
+	for _, format := range []string{"table", "jsonpath={.items[*].id}", "template-list={{len .items}}"} {
+		w, err := newTailEventWriter(io.Discard, format, defaultListKeyOrder)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w != nil {
+			t.Errorf("expected the %s format to be rendered for each poll", format)
+		}
+	}
+}
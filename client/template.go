@@ -0,0 +1,220 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+// customPrintFormats are the output formats, which require an argument, e.g.
+// "template={{.Action}}"
+var customPrintFormats = []string{
+	"template",
+	"template-file",
+	"template-list",
+	"template-list-file",
+	"jsonpath",
+}
+
+// parseCustomFormat splits a custom format into its name and its argument.
+// It returns false, when the format is not a custom format.
+func parseCustomFormat(format string) (name, arg string, ok bool) {
+	name, arg, ok = strings.Cut(format, "=")
+	if !ok || !slices.Contains(customPrintFormats, name) {
+		return "", "", false
+	}
+	return name, arg, true
+}
+
+// isListFormat returns true for the custom formats, which are applied once
+// to the list of all events
+func isListFormat(format string) bool {
+	name, _, ok := parseCustomFormat(format)
+	return ok && (name == "jsonpath" || strings.HasPrefix(name, "template-list"))
+}
+
+// templateFuncs are the helper functions available in Go templates
+var templateFuncs = template.FuncMap{
+	// formatTime formats a Hermes event time with a Go time layout in the
+	// time zone of the --timezone flag
+	"formatTime": func(layout, eventTime string) string {
+		t, err := parseTime(eventTime)
+		if err != nil {
+			return eventTime
+		}
+		if timezone != nil {
+			t = t.In(timezone)
+		}
+		return t.Format(layout)
+	},
+	// truncate shortens the string to n characters
+	"truncate": func(n int, s string) string {
+		runes := []rune(s)
+		if n < 0 || len(runes) <= n {
+			return s
+		}
+		if n <= 3 {
+			return string(runes[:n])
+		}
+		return string(runes[:n-3]) + "..."
+	},
+	// attachment returns the content of the event or target attachment with
+	// the name, JSON contents are decoded
+	"attachment": func(name string, evt events.Event) any {
+		for _, attachment := range slices.Concat(evt.Attachments, evt.Target.Attachments) {
+			if attachment.Name != name {
+				continue
+			}
+			if s, ok := attachment.Content.(string); ok {
+				var parsed any
+				if err := json.Unmarshal([]byte(s), &parsed); err == nil {
+					return parsed
+				}
+			}
+			return attachment.Content
+		}
+		return nil
+	},
+	// field returns the value of a field path like --column does
+	"field": func(path string, evt events.Event) string {
+		v, _ := resolveFieldPath(evt, path)
+		return v
+	},
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// newCustomEventWriter returns the writer of a template or a JSONPath
+// format. It returns false, when the format is not a custom format.
+func newCustomEventWriter(w io.Writer, format string) (eventWriter, bool, error) {
+	name, arg, ok := parseCustomFormat(format)
+	if !ok {
+		return nil, false, nil
+	}
+
+	switch name {
+	case "jsonpath":
+		tmpl, err := parseJSONPathTemplate(arg)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to parse the JSONPath template: %w", err)
+		}
+		return &jsonPathEventWriter{w: w, tmpl: tmpl, allEvents: []events.Event{}}, true, nil
+	case "template-file", "template-list-file":
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to read the template file: %w", err)
+		}
+		arg = string(data)
+	}
+
+	tmpl, err := template.New("event").Funcs(templateFuncs).Parse(arg)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to parse the template: %w", err)
+	}
+	if isListFormat(format) {
+		return &templateListEventWriter{w: w, tmpl: tmpl, allEvents: []events.Event{}}, true, nil
+	}
+	return &templateEventWriter{w: w, tmpl: tmpl}, true, nil
+}
+
+// templateEventWriter applies a Go template to each event. The output of
+// each event is terminated by a newline.
+type templateEventWriter struct {
+	w    io.Writer
+	tmpl *template.Template
+}
+
+func (t *templateEventWriter) Write(allEvents []events.Event) error {
+	var buf bytes.Buffer
+	for _, evt := range allEvents {
+		buf.Reset()
+		if err := t.tmpl.Execute(&buf, evt); err != nil {
+			return fmt.Errorf("failed to execute the template for the %s event: %w", evt.ID, err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := buf.WriteTo(t.w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *templateEventWriter) Close() error {
+	return nil
+}
+
+// templateListEventWriter applies a Go template once to the list of all
+// events, e.g. "{{range .items}}{{.ID}} {{end}}". The events are held back
+// until Close.
+type templateListEventWriter struct {
+	w         io.Writer
+	tmpl      *template.Template
+	allEvents []events.Event
+}
+
+func (t *templateListEventWriter) Write(allEvents []events.Event) error {
+	t.allEvents = append(t.allEvents, allEvents...)
+	return nil
+}
+
+func (t *templateListEventWriter) Close() error {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, map[string]any{"items": t.allEvents}); err != nil {
+		return fmt.Errorf("failed to execute the template: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := buf.WriteTo(t.w)
+	return err
+}
+
+// jsonPathEventWriter applies a JSONPath template to the list of all events
+// like kubectl does, e.g. "{.items[*].id}". The events are held back until
+// Close.
+type jsonPathEventWriter struct {
+	w         io.Writer
+	tmpl      *jsonPathTemplate
+	allEvents []events.Event
+}
+
+func (j *jsonPathEventWriter) Write(allEvents []events.Event) error {
+	j.allEvents = append(j.allEvents, allEvents...)
+	return nil
+}
+
+func (j *jsonPathEventWriter) Close() error {
+	// convert the events into generic JSON values to match the JSON names
+	data, err := json.Marshal(map[string]any{"items": j.allEvents})
+	if err != nil {
+		return err
+	}
+	var list any
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := j.tmpl.Execute(&buf, list); err != nil {
+		return fmt.Errorf("failed to execute the JSONPath template: %w", err)
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err = buf.WriteTo(j.w)
+	return err
+}
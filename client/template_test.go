@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+var templateTestEvents = []events.Event{
+	{
+		ID:        "1",
+		EventTime: "2026-10-01T10:15:00.000+0000",
+		Action:    cadf.UpdateAction,
+		Outcome:   cadf.SuccessOutcome,
+		Initiator: cadf.Resource{Name: "alice-admin", Host: &cadf.Host{Address: "10.0.0.1"}},
+		Target:    cadf.Resource{ID: "server-1"},
+		Attachments: []cadf.Attachment{
+			{Name: "spec", Content: `{"size":3}`},
+		},
+	},
+	{
+		ID:        "2",
+		EventTime: "2026-10-01T11:00:00.000+0000",
+		Action:    cadf.DeleteAction,
+		Outcome:   cadf.FailureOutcome,
+		Initiator: cadf.Resource{Name: "bob"},
+		Target:    cadf.Resource{ID: "server-2"},
+	},
+}
+
+func TestCustomEventWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "event.tmpl")
+	if err := os.WriteFile(file, []byte(`{{.ID}}: {{.Target.ID}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	listFile := filepath.Join(t.TempDir(), "events.tmpl")
+	if err := os.WriteFile(listFile, []byte("{{range .items}}{{.Target.ID}}\n{{end}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		Format   string
+		Expected string
+	}{
+		{`template={{.Initiator.Name}} {{.Action}}`, "alice-admin update\nbob delete\n"},
+		{`template={{formatTime "15:04" .EventTime}} {{truncate 6 .Initiator.Name}}`, "10:15 ali...\n11:00 bob\n"},
+		{`template={{with attachment "spec" .}}{{.size}}{{end}}|{{field "Initiator.Host.Address" .}}`, "3|10.0.0.1\n|\n"},
+		{"template-file=" + file, "1: server-1\n2: server-2\n"},
+		{`template-list={{len .items}} events:{{range .items}} {{.ID}}{{end}}`, "2 events: 1 2\n"},
+		{`template-list={{range $i, $e := .items}}{{if $i}},{{end}}{{truncate 5 $e.Initiator.Name}}{{end}}`, "al...,bob\n"},
+		{"template-list-file=" + listFile, "server-1\nserver-2\n"},
+		{`template-list={{with .items}}{{end}}`, ""},
+		{`jsonpath={.items[*].id}`, "1 2\n"},
+		{`jsonpath={.items[0].initiator.host.address}`, "10.0.0.1\n"},
+		{`jsonpath={.items[-1].target.id} {.items[0:1].action}`, "server-2 update\n"},
+		{`jsonpath={range .items[*]}{.id}{"\t"}{.outcome}{"\n"}{end}`, "1\tsuccess\n2\tfailure\n"},
+		{`jsonpath={.items[?(@.outcome=="failure")].initiator.name}`, "bob\n"},
+		{`jsonpath={.items[?(@.initiator.host)].id} {..address}`, "1 10.0.0.1\n"},
+		{`jsonpath={$.items[*].missing}`, ""},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		w, ok, err := newCustomEventWriter(&buf, c.Format)
+		if !ok || err != nil {
+			t.Errorf("expected %q to be a valid format: %v", c.Format, err)
+			continue
+		}
+		if err := w.Write(templateTestEvents); err != nil {
+			t.Errorf("failed to write %q: %s", c.Format, err)
+			continue
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("failed to close %q: %s", c.Format, err)
+			continue
+		}
+		if buf.String() != c.Expected {
+			t.Errorf("expected %q to print %q, got %q", c.Format, c.Expected, buf.String())
+		}
+	}
+}
+
+func TestCustomEventWriterErrors(t *testing.T) {
+	for _, format := range []string{"table", "template", "value=x"} {
+		if _, ok, _ := newCustomEventWriter(nil, format); ok {
+			t.Errorf("expected %q not to be a custom format", format)
+		}
+	}
+	for _, format := range []string{
+		"template={{.ID",
+		"template-file=" + filepath.Join(t.TempDir(), "missing"),
+		"template-list={{range .items}}",
+		"template-list-file=" + filepath.Join(t.TempDir(), "missing"),
+		"jsonpath={.items",
+		"jsonpath={range .items[*]}{.id}",
+		"jsonpath={.id}{end}",
+		"jsonpath={.items[x]}",
+		"jsonpath={items}",
+		`jsonpath={.items[?(.id=="1")]}`,
+	} {
+		if _, ok, err := newCustomEventWriter(nil, format); !ok || err == nil {
+			t.Errorf("expected %q to fail", format)
+		}
+	}
+}
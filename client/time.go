@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/viper"
+)
+
+// hermesTimeFormat is the time format used by Hermes in the event time
+const hermesTimeFormat = "2006-01-02T15:04:05-0700"
+
+// timezone is used to interpret time expressions without a time zone and to
+// print event times in the table, csv and value formats. It is set by
+// verifyGlobalFlags, nil keeps the event times as returned by Hermes.
+var timezone *time.Location
+
+var relativeTimeRx = regexp.MustCompile(`^([+-]?)((?:\d+[smhdw])+)$`)
+var relativeTimeUnitRx = regexp.MustCompile(`(\d+)([smhdw])`)
+
+var relativeTimeUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// sumDurationUnits returns the sum of the duration units, e.g. of "1w2d".
+func sumDurationUnits(units string) (time.Duration, error) {
+	var d time.Duration
+	for _, u := range relativeTimeUnitRx.FindAllStringSubmatch(units, -1) {
+		v, err := strconv.Atoi(u[1])
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(v) * relativeTimeUnits[u[2]]
+	}
+	return d, nil
+}
+
+// parseDuration parses an unsigned duration with the units of relative time
+// expressions, e.g. "90d" or "1w2d".
+func parseDuration(expr string) (time.Duration, error) {
+	m := relativeTimeRx.FindStringSubmatch(strings.TrimSpace(expr))
+	if m == nil || m[1] != "" {
+		return 0, fmt.Errorf("unsupported %q duration, supported are durations like 12h, 90d or 1w2d", expr)
+	}
+	d, err := sumDurationUnits(m[2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid %q duration: %w", expr, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid %q duration: must be positive", expr)
+	}
+	return d, nil
+}
+
+// parseTimeExpr parses an absolute or a relative time expression. Supported
+// expressions are:
+//
+//   - "now", "today" and "yesterday"
+//   - durations relative to now, e.g. "2h", "-7d" or "+1w2d"; unsigned
+//     durations point to the past
+//   - Unix epoch seconds, optionally prefixed with "@" like date(1) does,
+//     e.g. "1714521600" or "@1714521600"
+//   - RFC3339 timestamps, timestamps without a time zone and dates, e.g.
+//     "2024-05-01"
+//
+// Expressions without a time zone are interpreted in the loc time zone, UTC
+// is used when loc is nil.
+func parseTimeExpr(expr string, now time.Time, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	expr = strings.TrimSpace(expr)
+	now = now.In(loc)
+
+	switch strings.ToLower(expr) {
+	case "":
+		return time.Time{}, errors.New("empty time expression")
+	case "now":
+		return now, nil
+	case "today":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	case "yesterday":
+		return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, loc), nil
+	}
+
+	if m := relativeTimeRx.FindStringSubmatch(expr); m != nil {
+		d, err := sumDurationUnits(m[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid %q relative time: %w", expr, err)
+		}
+		if m[1] == "+" {
+			return now.Add(d), nil
+		}
+		return now.Add(-d), nil
+	}
+
+	epoch, prefixed := strings.CutPrefix(expr, "@")
+	if v, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+		return time.Unix(v, 0).In(loc), nil
+	} else if prefixed {
+		return time.Time{}, fmt.Errorf("invalid %q Unix epoch time: %w", expr, err)
+	}
+
+	// time formats with a time zone
+	for _, layout := range []string{time.RFC3339, hermesTimeFormat} {
+		if t, err := time.Parse(layout, expr); err == nil {
+			return t, nil
+		}
+	}
+
+	// time formats without a time zone
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, expr, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unsupported %q time expression, supported are RFC3339 timestamps, dates, Unix epoch seconds like 1714521600, durations like 2h or -7d, now, today and yesterday", expr)
+}
+
+// formatEventTime converts the Hermes event time into the timezone.
+func formatEventTime(eventTime string) string {
+	if timezone == nil {
+		return eventTime
+	}
+	t, err := parseTime(eventTime)
+	if err != nil {
+		return eventTime
+	}
+	return t.In(timezone).Format(hermesTimeFormat)
+}
+
+// buildTimeFilter converts the time, time-start, since and time-end flags into
+// Hermes time filters.
+func buildTimeFilter(now time.Time) ([]events.DateQuery, error) {
+	var result []events.DateQuery
+
+	if t := viper.GetString("time"); t != "" {
+		rt, err := parseTimeExpr(t, now, timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time: %w", err)
+		}
+		result = append(result, events.DateQuery{
+			Date: rt,
+		})
+	}
+
+	for _, flag := range []string{"time-start", "since"} {
+		if t := viper.GetString(flag); t != "" {
+			rt, err := parseTimeExpr(t, now, timezone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", flag, err)
+			}
+			result = append(result, events.DateQuery{
+				Date:   rt,
+				Filter: events.DateFilterGTE,
+			})
+		}
+	}
+
+	if t := viper.GetString("time-end"); t != "" {
+		rt, err := parseTimeExpr(t, now, timezone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse time-end: %w", err)
+		}
+		result = append(result, events.DateQuery{
+			Date:   rt,
+			Filter: events.DateFilterLTE,
+		})
+	}
+
+	return result, nil
+}
+
+// verifyTimeFlags verifies that the time flags can be combined.
+func verifyTimeFlags() error {
+	teq := viper.GetString("time")
+	tgt := viper.GetString("time-start")
+	tlt := viper.GetString("time-end")
+	since := viper.GetString("since")
+	if teq != "" && (tgt != "" || tlt != "" || since != "") {
+		return errors.New("cannot combine time flag with time-start, time-end or since flags")
+	}
+	if tgt != "" && since != "" {
+		return errors.New("cannot combine time-start flag with since flag")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeExpr(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data is not available: %s", err)
+	}
+	now := time.Date(2024, 5, 2, 10, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		Expr     string
+		Location *time.Location
+		Expected time.Time
+	}{
+		{"now", nil, now},
+		{"2h", nil, now.Add(-2 * time.Hour)},
+		{"-7d", nil, now.Add(-7 * 24 * time.Hour)},
+		{"+1w2d", nil, now.Add(9 * 24 * time.Hour)},
+		{"1h30m", nil, now.Add(-90 * time.Minute)},
+		{"today", nil, time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"today", berlin, time.Date(2024, 5, 2, 0, 0, 0, 0, berlin)},
+		{"@1714521600", nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"1714521600", nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"1714521600", berlin, time.Date(2024, 5, 1, 2, 0, 0, 0, berlin)},
+		{"2024-05-01", nil, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-05-01", berlin, time.Date(2024, 5, 1, 0, 0, 0, 0, berlin)},
+		{"2024-05-01T12:00:00", berlin, time.Date(2024, 5, 1, 12, 0, 0, 0, berlin)},
+		{"2024-05-01T12:00:00Z", berlin, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{"2019-04-23T22:07:16+0000", berlin, time.Date(2019, 4, 23, 22, 7, 16, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		result, err := parseTimeExpr(c.Expr, now, c.Location)
+		if err != nil {
+			t.Errorf("failed to parse %q: %s", c.Expr, err)
+			continue
+		}
+		if !result.Equal(c.Expected) {
+			t.Errorf("expected %q to be parsed as %s, got %s", c.Expr, c.Expected, result)
+		}
+	}
+
+	for _, expr := range []string{"", "2x", "tomorrowish", "2024-13-01", "@", "@2024-05-01", "@17145x"} {
+		if _, err := parseTimeExpr(expr, now, nil); err == nil {
+			t.Errorf("expected %q to fail", expr)
+		}
+	}
+}
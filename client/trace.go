@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultTraceWindow is the time range around the traced event, which is
+// searched for events of the same request
+const defaultTraceWindow = time.Hour
+
+var defaultTraceKeyOrder = []string{
+	"Time",
+	"Source",
+	"Action",
+	"Outcome",
+	"Target",
+	"Initiator",
+	"RequestID",
+}
+
+// traceRequestID returns the ID, which correlates the event with the events
+// of other services: the global request ID of the initiator or its request
+// ID, when the request was not forwarded by another service.
+func traceRequestID(evt events.Event) string {
+	return cmp.Or(evt.Initiator.GlobalRequestID, evt.Initiator.RequestID)
+}
+
+// hasRequestID returns true, when the event belongs to the request.
+func hasRequestID(evt events.Event, requestID string) bool {
+	return evt.Initiator.GlobalRequestID == requestID || evt.Initiator.RequestID == requestID
+}
+
+// findRequestEvents returns the events of the request in chronological
+// order. The Hermes search matches the request ID anywhere in the event,
+// therefore the results are filtered by the request IDs of the initiator.
+// With scan, all events matching the list options are fetched and filtered
+// locally.
+func findRequestEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, requestID string, scan bool, concurrency int) ([]events.Event, error) {
+	if !scan {
+		listOpts.Search = requestID
+	}
+	listOpts.Sort = "time:asc"
+
+	var result []events.Event
+	var bar *pb.ProgressBar
+	err := getEvents(ctx, client, listOpts, 0, concurrency, &bar, func(page []events.Event) error {
+		for _, evt := range page {
+			if hasRequestID(evt, requestID) {
+				result = append(result, evt)
+			}
+		}
+		return nil
+	})
+	if bar != nil {
+		bar.Finish()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// traceEvents returns the events of the request and the ID, which correlates
+// them. When the request ID is the local request ID of a service, which was
+// called with a global request ID, the events of the global request ID are
+// returned.
+func traceEvents(ctx context.Context, client *gophercloud.ServiceClient, listOpts events.ListOpts, requestID string, scan bool, concurrency int) (string, []events.Event, error) {
+	result, err := findRequestEvents(ctx, client, listOpts, requestID, scan, concurrency)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, evt := range result {
+		if id := evt.Initiator.GlobalRequestID; id != "" && id != requestID {
+			result, err = findRequestEvents(ctx, client, listOpts, id, scan, concurrency)
+			return id, result, err
+		}
+	}
+	return requestID, result, nil
+}
+
+// countServices returns the amount of distinct observers of the events.
+func countServices(allEvents []events.Event) int {
+	services := make(map[string]bool)
+	for _, evt := range allEvents {
+		services[evt.Observer.TypeURI] = true
+	}
+	return len(services)
+}
+
+// traceDuration returns the time between the first and the last of the
+// chronologically ordered events.
+func traceDuration(allEvents []events.Event) time.Duration {
+	first, err := parseTime(allEvents[0].EventTime)
+	if err != nil {
+		return 0
+	}
+	last, err := parseTime(allEvents[len(allEvents)-1].EventTime)
+	if err != nil {
+		return 0
+	}
+	return last.Sub(first)
+}
+
+// TraceCmd represents the trace command
+var TraceCmd = &cobra.Command{
+	Use:   "trace <event-id|request-id>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Trace a request across services",
+	Long: `Print the events of all services, which share the global request ID of an event or of a request ID,
+as a chronological timeline. IDs starting with "req-" are request IDs, other IDs are looked up as events first.
+Related events are searched in --window around the traced event, unless a time flag is specified.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return err
+		}
+
+		if err := verifyTimeFlags(); err != nil {
+			return err
+		}
+		if viper.GetDuration("window") <= 0 {
+			return errors.New("--window must be positive")
+		}
+
+		return verifyGlobalFlags(defaultTraceKeyOrder)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		keyOrder := viper.GetStringSlice("column")
+		if len(keyOrder) == 0 {
+			keyOrder = defaultTraceKeyOrder
+		}
+		scan := viper.GetBool("scan")
+
+		timeFilter, err := buildTimeFilter(time.Now())
+		if err != nil {
+			return err
+		}
+		projectID, domainID := getScope()
+		listOpts := events.ListOpts{
+			Limit:     maxOffset,
+			ProjectID: projectID,
+			DomainID:  domainID,
+			Time:      timeFilter,
+		}
+
+		client, err := NewHermesV1Client(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create Hermes client: %w", err)
+		}
+
+		// resolve the request ID of the event
+		requestID := args[0]
+		if !strings.HasPrefix(requestID, "req-") {
+			getOpts := events.GetOpts{
+				ProjectID: projectID,
+				DomainID:  domainID,
+			}
+			event, err := events.Get(ctx, client, requestID, getOpts).Extract()
+			switch {
+			case gophercloud.ResponseCodeIs(err, http.StatusNotFound):
+				// not an event ID, try it as a request ID
+			case err != nil:
+				return fmt.Errorf("failed to get %s event: %w", requestID, err)
+			default:
+				requestID = traceRequestID(*event)
+				if requestID == "" {
+					return fmt.Errorf("the %s event has no request ID", args[0])
+				}
+				if len(listOpts.Time) == 0 {
+					t, err := parseTime(event.EventTime)
+					if err != nil {
+						return fmt.Errorf("failed to parse time of the %s event: %w", event.ID, err)
+					}
+					window := viper.GetDuration("window")
+					listOpts.Time = []events.DateQuery{
+						{Date: t.Add(-window), Filter: events.DateFilterGTE},
+						{Date: t.Add(window), Filter: events.DateFilterLTE},
+					}
+				}
+			}
+		}
+		if scan && len(listOpts.Time) == 0 {
+			return errors.New("--scan requires a time flag, when a request ID is traced")
+		}
+
+		traceID, allEvents, err := traceEvents(ctx, client, listOpts, requestID, scan, viper.GetInt("concurrency"))
+		if err != nil {
+			return fmt.Errorf("failed to trace the %s request: %w", requestID, err)
+		}
+		if len(allEvents) == 0 {
+			return fmt.Errorf("no events of the %s request found", requestID)
+		}
+
+		fmt.Fprintf(os.Stderr, "Found %d events of the %s request in %d services within %s\n", len(allEvents), traceID, countServices(allEvents), traceDuration(allEvents))
+
+		return printEvent(allEvents, viper.GetString("format"), keyOrder)
+	},
+}
+
+func init() {
+	initTraceCmdFlags()
+	RootCmd.AddCommand(TraceCmd)
+}
+
+func initTraceCmdFlags() {
+	addScopeFlags(TraceCmd.Flags(), "trace events of")
+	addTimeFilterFlags(TraceCmd.Flags())
+	TraceCmd.Flags().DurationP("window", "w", defaultTraceWindow, "time range before and after the traced event to search for events of the same request")
+	TraceCmd.Flags().BoolP("scan", "", false, "fetch all events of the time range and match the request ID locally instead of using the Hermes search")
+	TraceCmd.Flags().UintP("concurrency", "", defaultConcurrency, "amount of parallel requests, when more than 10000 events are fetched")
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/gophercloud-sapcc/v2/audit/v1/events"
+)
+
+func TestTraceRequestID(t *testing.T) {
+	forwarded := events.Event{ID: "1", EventTime: "2024-01-15T09:00:00.000+0000", Observer: cadf.Resource{TypeURI: "service/network"}, Initiator: cadf.Resource{RequestID: "req-2", GlobalRequestID: "req-1"}}
+	origin := events.Event{ID: "2", EventTime: "2024-01-15T09:00:02.500+0000", Observer: cadf.Resource{TypeURI: "service/compute"}, Initiator: cadf.Resource{RequestID: "req-1"}}
+
+	if id := traceRequestID(forwarded); id != "req-1" {
+		t.Errorf("expected the global request ID, got %q", id)
+	}
+	if id := traceRequestID(origin); id != "req-1" {
+		t.Errorf("expected the request ID, got %q", id)
+	}
+
+	cases := []struct {
+		Event     events.Event
+		RequestID string
+		Expected  bool
+	}{
+		{forwarded, "req-1", true},
+		{forwarded, "req-2", true},
+		{origin, "req-1", true},
+		{origin, "req-2", false},
+		{events.Event{}, "req-1", false},
+	}
+	for _, c := range cases {
+		if result := hasRequestID(c.Event, c.RequestID); result != c.Expected {
+			t.Errorf("expected the %s event to match the %s request: %t, got %t", c.Event.ID, c.RequestID, c.Expected, result)
+		}
+	}
+
+	allEvents := []events.Event{forwarded, origin}
+	if n := countServices(allEvents); n != 2 {
+		t.Errorf("expected 2 services, got %d", n)
+	}
+	if d := traceDuration(allEvents); d != 2500*time.Millisecond {
+		t.Errorf("expected 2.5s, got %s", d)
+	}
+}
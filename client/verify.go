@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"filippo.io/age"
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// verifyResult is the outcome of an export file verification
+type verifyResult struct {
+	Manifest integrityManifest `json:"manifest"`
+	Checks   []integrityCheck  `json:"checks"`
+	OK       bool              `json:"ok"`
+}
+
+// VerifyExportCmd represents the verify-export command
+var VerifyExportCmd = &cobra.Command{
+	Use:   "verify-export <object-name>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Verify an exported file against its integrity manifest",
+	Long: `Download an exported file, recompute its checksums, event count and time range and compare
+them with the integrity manifest, which was written next to the file by the export command.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := viper.BindPFlags(cmd.Flags()); err != nil {
+			return fmt.Errorf("failed to bind flags: %w", err)
+		}
+		spec, err := getExportDestination(viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+		if spec.Kind == destinationStdout {
+			return errors.New("exports to stdout cannot be verified")
+		}
+		return verifyGlobalFlags(nil)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		destination, err := openExportLocation(ctx, viper.GetString("output"), viper.GetString("container"))
+		if err != nil {
+			return err
+		}
+
+		var pubkey ed25519.PublicKey
+		if path := viper.GetString("pubkey"); path != "" {
+			data, err := readKeyMaterial(path, "")
+			if err != nil {
+				return err
+			}
+			pubkey, err = parsePublicKey(data)
+			if err != nil {
+				return err
+			}
+		}
+
+		m, manifestSHA256, err := loadIntegrityManifest(ctx, destination, args[0])
+		if err != nil {
+			return err
+		}
+		var identities []age.Identity
+		if len(m.Encryption) > 0 {
+			identities, err = loadIdentities(viper.GetStringSlice("identity"), viper.GetString("passphrase-cmd"))
+			if err != nil {
+				return err
+			}
+		}
+		checks, err := verifyExportFile(ctx, destination, m, identities)
+		if err != nil {
+			return err
+		}
+		if pubkey != nil {
+			// the signature of the export file covers the actual content,
+			// which was verified against the manifest above
+			signatures := []struct{ Name, Object, SHA256 string }{
+				{"Signature", m.Object, checks[0].Actual},
+				{"Manifest signature", integrityManifestName(m.Object), manifestSHA256},
+			}
+			for _, s := range signatures {
+				check, err := verifySignature(ctx, destination, pubkey, s.Name, s.Object, s.SHA256)
+				if err != nil {
+					return err
+				}
+				checks = append(checks, check)
+			}
+		}
+		result := verifyResult{Manifest: m, Checks: checks, OK: true}
+		for _, c := range checks {
+			result.OK = result.OK && c.OK
+		}
+
+		switch viper.GetString("format") {
+		case "json":
+			jsonResult, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", jsonResult)
+		case "yaml":
+			yamlResult, err := yaml.Marshal(result)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s", yamlResult)
+		default:
+			table := tablewriter.NewTable(os.Stdout,
+				tablewriter.WithRowAlignment(tw.AlignLeft),
+			)
+			table.Header("Check", "Expected", "Actual", "Result")
+			rows := make([][]string, 0, len(checks))
+			for _, c := range checks {
+				status := "OK"
+				if !c.OK {
+					status = "MISMATCH"
+				}
+				rows = append(rows, []string{c.Name, c.Expected, c.Actual, status})
+			}
+			if err := table.Bulk(rows); err != nil {
+				return fmt.Errorf("error appending rows to table: %w", err)
+			}
+			if err := table.Render(); err != nil {
+				return fmt.Errorf("error rendering table: %w", err)
+			}
+
+			filters, err := json.Marshal(m.Filters)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nExported by hermescli %s at %s\n", m.Version, m.CreatedAt.Format(time.RFC3339))
+			fmt.Printf("Hermes total: %d\n", m.HermesTotal)
+			fmt.Printf("Filters: %s\n", filters)
+		}
+
+		if !result.OK {
+			return fmt.Errorf("%s does not match its integrity manifest", args[0])
+		}
+		return nil
+	},
+}
+
+func init() {
+	VerifyExportCmd.Flags().StringP("output", "o", "", "location of the export: swift://container/prefix or file:///path/to/directory")
+	VerifyExportCmd.Flags().String("container", "", "Swift container name (alias for --output swift://container)")
+	VerifyExportCmd.Flags().StringSlice("identity", nil, "age identity file to decrypt encrypted exports, can be specified multiple times")
+	VerifyExportCmd.Flags().String("passphrase-cmd", "", "command, which prints the passphrase to decrypt encrypted exports")
+	VerifyExportCmd.Flags().String("pubkey", "", "ed25519 public key file to verify the signatures of the export file and its manifest")
+	RootCmd.AddCommand(VerifyExportCmd)
+}